@@ -1,5 +1,8 @@
 package alu
 
+//go:generate mockgen -destination=mocks/condition_flags_mock.go -package=alu github.com/cbush06/intel8080emulator/alu ConditionFlags
+//go:generate mockgen -destination=mocks/alu_mock.go -package=alu github.com/cbush06/intel8080emulator/alu ALU
+
 import (
 	"github.com/cbush06/intel8080emulator/memory"
 )
@@ -9,7 +12,7 @@ type ALU interface {
 	ConditionFlags
 	GetA() *memory.Register
 	SetA(a *memory.Register)
-	UpdateFlags(original uint8, result uint16)
+	UpdateFlags(operand1 uint8, operand2 uint8, result uint16)
 	UpdateFlagsExceptCarry(value uint8)
 	AddImmediate(addend uint8)
 	AddImmediateWithCarry(addend uint8)
@@ -5,8 +5,9 @@ import (
 )
 
 // ALUImpl represents the collection of components that make up the Intel 8080's Arithmetic Logic Unit,
-// specifically, it contains the ALUImpl's condition flags. Note that the Auxillary Carry flag is not
-// implemented.
+// specifically, it contains the ALUImpl's condition flags. Like the 8080, it performs subtraction by adding the
+// complement of the subtrahend, so the Carry flag records a borrow and the Auxiliary Carry flag records the
+// inverse of a borrow out of bit 4.
 type ALUImpl struct {
 	A *memory.Register
 	ConditionFlags
@@ -30,8 +31,10 @@ func (alu *ALUImpl) SetA(a *memory.Register) {
 	alu.A = a
 }
 
-// UpdateFlags updates all ALU flags according to the value provided.
-func (alu *ALUImpl) UpdateFlags(original uint8, result uint16) {
+// UpdateFlags updates all ALU flags according to the result of adding operand1 and operand2 (plus any carry in).
+// The Carry flag is set if result carried out of bit 7 and the Auxiliary Carry flag if the addition carried out
+// of bit 3.
+func (alu *ALUImpl) UpdateFlags(operand1 uint8, operand2 uint8, result uint16) {
 	var resultMasked = uint8(result & 0xFF)
 
 	alu.ClearFlags()
@@ -39,12 +42,12 @@ func (alu *ALUImpl) UpdateFlags(original uint8, result uint16) {
 	alu.UpdateSign(resultMasked)
 	alu.UpdateParity(resultMasked)
 	alu.UpdateCarry(result)
-	alu.UpdateAuxiliaryCarry(original, resultMasked)
+	alu.UpdateAuxiliaryCarry(operand1, operand2, resultMasked)
 }
 
-// UpdateFlagsExceptCarry updates all ALU flags except the Carry flag according to the value provided.
+// UpdateFlagsExceptCarry updates the Zero, Sign and Parity flags according to the value provided. The Carry and
+// Auxiliary Carry flags are left unchanged.
 func (alu *ALUImpl) UpdateFlagsExceptCarry(value uint8) {
-	alu.ClearFlags()
 	alu.UpdateZero(value)
 	alu.UpdateSign(value)
 	alu.UpdateParity(value)
@@ -57,7 +60,7 @@ func (alu *ALUImpl) AddImmediate(addend uint8) {
 	alu.A.Read8(&accum)
 
 	result := uint16(addend) + uint16(accum)
-	alu.UpdateFlags(accum, result)
+	alu.UpdateFlags(accum, addend, result)
 	alu.A.Write8(uint8(result & 0xFF))
 }
 
@@ -73,7 +76,7 @@ func (alu *ALUImpl) AddImmediateWithCarry(addend uint8) {
 	}
 
 	result := uint16(accum) + uint16(carry) + uint16(addend)
-	alu.UpdateFlags(accum, result)
+	alu.UpdateFlags(accum, addend, result)
 	alu.A.Write8(uint8(result & 0xFF))
 }
 
@@ -88,29 +91,44 @@ func (alu *ALUImpl) DoubleAdd(addend1 uint16, addend2 uint16) uint16 {
 // SubImmediate subtracts the subtrahend from the content of the accumulator. The result is placed in the accumulator.
 // The AluFlags will be updated based on this operation's result.
 func (alu *ALUImpl) SubImmediate(subtrahend uint8) {
-	var minuend uint8
-	alu.A.Read8(&minuend)
-
-	difference := uint16(minuend) - uint16(subtrahend)
-	alu.UpdateFlags(minuend, difference)
-	alu.A.Write8(uint8(difference & 0xFF))
+	alu.A.Write8(alu.subtract(subtrahend, false))
 }
 
 // SubImmediateWithBorrow subtracts the subtrahend and the carry flag from the accumulator. The result is placed in
 // the accumulator. The AluFlags will be updated based on this operation's result.
 func (alu *ALUImpl) SubImmediateWithBorrow(subtrahend uint8) {
-	var borrow uint8
-	if alu.IsCarry() {
-		borrow = 1
+	alu.A.Write8(alu.subtract(subtrahend, alu.IsCarry()))
+}
+
+// subtract computes (A) - subtrahend - borrow the way the 8080 does, by adding the one's complement of the
+// subtrahend and the inverse of the borrow to the accumulator, and returns the difference. All flags are updated:
+// CY is set if the adder did not carry out of bit 7 (a borrow occurred), and AC is the adder's carry out of bit 3.
+// The accumulator is not modified.
+func (alu *ALUImpl) subtract(subtrahend uint8, borrow bool) uint8 {
+	var minuend uint8
+	alu.A.Read8(&minuend)
+
+	var carryIn uint16 = 1
+	if borrow {
+		carryIn = 0
+	}
+
+	sum := uint16(minuend) + uint16(^subtrahend) + carryIn
+	alu.UpdateFlags(minuend, ^subtrahend, sum)
+
+	if sum > 0xFF {
+		alu.ClearCarry()
+	} else {
+		alu.SetCarry()
 	}
-	alu.SubImmediate(subtrahend + borrow)
+	return uint8(sum & 0xFF)
 }
 
 // Increment increments a given value and updates all flags except the Carry flag, accordingly.
 func (alu *ALUImpl) Increment(value uint8) uint8 {
 	result := value + 1
 	alu.UpdateFlagsExceptCarry(result)
-	alu.UpdateAuxiliaryCarry(value, result)
+	alu.UpdateAuxiliaryCarry(value, 0x01, result)
 	return result
 }
 
@@ -124,11 +142,12 @@ func (alu *ALUImpl) DecrementDouble(value uint16) uint16 {
 	return value - 1
 }
 
-// Decrement decrements a given value and updates all flags except the Carry flag, accordingly.
+// Decrement decrements a given value and updates all flags except the Carry flag, accordingly. As with the other
+// subtractions, the value is decremented by adding 0xFF, so AC is set unless the low-order four bits were zero.
 func (alu *ALUImpl) Decrement(value uint8) uint8 {
 	result := value - 1
 	alu.UpdateFlagsExceptCarry(result)
-	alu.UpdateAuxiliaryCarry(value, result)
+	alu.UpdateAuxiliaryCarry(value, 0xFF, result)
 	return result
 }
 
@@ -209,7 +228,8 @@ func (alu *ALUImpl) RotateLeftThroughCarry() {
 }
 
 // AndAccumulator performs a bitwise AND operation on the contents of the accumulator and the operand.
-// Flags Z, S, P, and AC are updated. The CY flag is cleared. The result is stored in the accumulator.
+// Flags Z, S, P, and AC are updated. The CY flag is cleared. The result is stored in the accumulator. AC is set
+// to bit 3 of the logical OR of the two operands, as on the 8080.
 func (alu *ALUImpl) AndAccumulator(operand uint8) {
 	var accum uint8
 	var result uint8
@@ -218,7 +238,11 @@ func (alu *ALUImpl) AndAccumulator(operand uint8) {
 	result = accum & operand
 	alu.A.Write8(result)
 	alu.UpdateFlagsExceptCarry(result)
-	alu.UpdateAuxiliaryCarry(accum, result)
+	if (accum|operand)&0x08 > 0 {
+		alu.SetAuxiliaryCarry()
+	} else {
+		alu.ClearAuxiliaryCarry()
+	}
 	alu.ClearCarry()
 }
 
@@ -273,24 +297,29 @@ func (alu *ALUImpl) DecimalAdjustAccumulator() {
 	var orig uint8
 	alu.A.Read8(&orig)
 
-	a := uint16(orig)
+	var correction uint8
+	carry := alu.IsCarry()
 
-	lsb := a & 0x0F
+	if orig&0x0F > 9 || alu.IsAuxiliaryCarry() {
+		correction |= 0x06
+	}
 
-	if lsb > 9 || alu.IsAuxiliaryCarry() {
-		a += 6
+	// The high-order digit is adjusted if it exceeds 9, or will once the low-order adjustment carries into it
+	if orig>>4 > 9 || carry || (orig>>4 >= 9 && orig&0x0F > 9) {
+		correction |= 0x60
+		carry = true
 	}
 
-	lsb = a & 0x0F
-	msb := a >> 4
+	result := uint16(orig) + uint16(correction)
+	alu.A.Write8(uint8(result & 0xFF))
+	alu.UpdateFlags(orig, correction, result)
 
-	if msb > 9 || alu.IsCarry() {
-		msb += 6
-		a = (msb << 4) | lsb
+	// CY is set by a high-order adjustment and otherwise keeps its previous value
+	if carry {
+		alu.SetCarry()
+	} else {
+		alu.ClearCarry()
 	}
-
-	alu.A.Write8(uint8(a & 0xFF))
-	alu.UpdateFlags(orig, a)
 }
 
 // ComplementAccumulator negates the value of the accumulator such that 1 bits become 0 bits and 0 bits become 1 bits.
@@ -304,21 +333,5 @@ func (alu *ALUImpl) ComplementAccumulator() {
 // Condition flags are updated as a result. The flags Z, S, P, CY, and AC are affected. The Z flag is set to 1 if
 // (A) = (r). The CY flag is set to 1 if (A) < (r).
 func (alu *ALUImpl) CompareAccumulator(operand uint8) {
-	var a uint8
-	alu.A.Read8(&a)
-
-	difference := a - operand
-
-	alu.UpdateSign(difference)
-	alu.UpdateParity(difference)
-	alu.UpdateAuxiliaryCarry(a, difference)
-
-	alu.ClearZero()
-	alu.ClearCarry()
-
-	if a == operand {
-		alu.SetZero()
-	} else if a < operand {
-		alu.SetCarry()
-	}
+	alu.subtract(operand, false)
 }
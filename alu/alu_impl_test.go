@@ -32,17 +32,16 @@ func TestALUImpl_SetA(t *testing.T) {
 	}
 }
 
-func expectUpdateFlags(cndFlags *alumock.MockConditionFlags, original uint8, new uint8) {
+func expectUpdateFlags(cndFlags *alumock.MockConditionFlags, operand1 uint8, operand2 uint8, result uint16) {
 	cndFlags.EXPECT().ClearFlags()
-	cndFlags.EXPECT().UpdateZero(new)
-	cndFlags.EXPECT().UpdateSign(new)
-	cndFlags.EXPECT().UpdateParity(new)
-	cndFlags.EXPECT().UpdateCarry(uint16(new))
-	cndFlags.EXPECT().UpdateAuxiliaryCarry(original, new)
+	cndFlags.EXPECT().UpdateZero(uint8(result))
+	cndFlags.EXPECT().UpdateSign(uint8(result))
+	cndFlags.EXPECT().UpdateParity(uint8(result))
+	cndFlags.EXPECT().UpdateCarry(result)
+	cndFlags.EXPECT().UpdateAuxiliaryCarry(operand1, operand2, uint8(result))
 }
 
 func expectUpdateFlagsExceptCarry(cndFlags *alumock.MockConditionFlags, value uint8) {
-	cndFlags.EXPECT().ClearFlags()
 	cndFlags.EXPECT().UpdateZero(value)
 	cndFlags.EXPECT().UpdateSign(value)
 	cndFlags.EXPECT().UpdateParity(value)
@@ -53,12 +52,12 @@ func TestALUImpl_UpdateFlags(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	expectUpdateFlags(cndFlags, 255, 255)
+	expectUpdateFlags(cndFlags, 255, 0, 255)
 
 	alu := &ALUImpl{
 		ConditionFlags: cndFlags,
 	}
-	alu.UpdateFlags(255, 255)
+	alu.UpdateFlags(255, 0, 255)
 }
 
 func TestALUImpl_UpdateFlagsExceptCarry(t *testing.T) {
@@ -66,7 +65,6 @@ func TestALUImpl_UpdateFlagsExceptCarry(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	cndFlags.EXPECT().ClearFlags()
 	cndFlags.EXPECT().UpdateZero(uint8(255))
 	cndFlags.EXPECT().UpdateSign(uint8(255))
 	cndFlags.EXPECT().UpdateParity(uint8(255))
@@ -82,7 +80,7 @@ func TestALUImpl_AddImmediate(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	expectUpdateFlags(cndFlags, 1, 2)
+	expectUpdateFlags(cndFlags, 1, 1, 2)
 
 	alu := &ALUImpl{
 		A:              memory.NewRegister(1),
@@ -104,7 +102,7 @@ func TestALUImpl_AddImmediateWithCarry(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	expectUpdateFlags(cndFlags, 1, 3)
+	expectUpdateFlags(cndFlags, 1, 1, 3)
 	cndFlags.EXPECT().IsCarry().Return(true)
 
 	alu := &ALUImpl{
@@ -143,7 +141,8 @@ func TestALUImpl_SubImmediate(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	expectUpdateFlags(cndFlags, 2, 1)
+	expectUpdateFlags(cndFlags, 2, 0xFE, 0x101) // 2 + ^1 + 1
+	cndFlags.EXPECT().ClearCarry()
 
 	alu := &ALUImpl{
 		A:              memory.NewRegister(2),
@@ -165,8 +164,9 @@ func TestALUImpl_SubImmediateWithBorrow(t *testing.T) {
 	defer ctrl.Finish()
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
-	expectUpdateFlags(cndFlags, 3, 1)
+	expectUpdateFlags(cndFlags, 3, 0xFE, 0x101) // 3 + ^1 + 0
 	cndFlags.EXPECT().IsCarry().Return(true)
+	cndFlags.EXPECT().ClearCarry()
 
 	alu := &ALUImpl{
 		A:              memory.NewRegister(3),
@@ -189,7 +189,7 @@ func TestALUImpl_Increment(t *testing.T) {
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
 	expectUpdateFlagsExceptCarry(cndFlags, 2)
-	cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(1), uint8(2))
+	cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(1), uint8(1), uint8(2))
 
 	alu := &ALUImpl{
 		ConditionFlags: cndFlags,
@@ -224,7 +224,7 @@ func TestALUImpl_Decrement(t *testing.T) {
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
 	expectUpdateFlagsExceptCarry(cndFlags, 1)
-	cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(2), uint8(1))
+	cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(2), uint8(0xFF), uint8(1))
 
 	alu := &ALUImpl{
 		ConditionFlags: cndFlags,
@@ -375,7 +375,7 @@ func TestALUImpl_AndAccumulator(t *testing.T) {
 
 	cndFlags := alumock.NewMockConditionFlags(ctrl)
 	expectUpdateFlagsExceptCarry(cndFlags, 0xA)
-	cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(0xA), uint8(0xA))
+	cndFlags.EXPECT().SetAuxiliaryCarry() // bit 3 of 0xA | 0xF
 	cndFlags.EXPECT().ClearCarry()
 
 	alu := &ALUImpl{
@@ -449,7 +449,8 @@ func TestALUImpl_DecimalAdjustAccumulator(t *testing.T) {
 		cndFlags := alumock.NewMockConditionFlags(ctrl)
 		cndFlags.EXPECT().IsAuxiliaryCarry().Return(true)
 		cndFlags.EXPECT().IsCarry().Return(false)
-		expectUpdateFlags(cndFlags, 0x11, 0x17)
+		expectUpdateFlags(cndFlags, 0x11, 0x06, 0x17)
+		cndFlags.EXPECT().ClearCarry()
 
 		alu := &ALUImpl{
 			A:              memory.NewRegister(0x11),
@@ -461,9 +462,10 @@ func TestALUImpl_DecimalAdjustAccumulator(t *testing.T) {
 
 	t.Run("Carry", func(t *testing.T) {
 		cndFlags := alumock.NewMockConditionFlags(ctrl)
-		cndFlags.EXPECT().IsAuxiliaryCarry().Return(true)
-		cndFlags.EXPECT().IsCarry().Return(false)
-		expectUpdateFlags(cndFlags, 0x11, 0x17)
+		cndFlags.EXPECT().IsAuxiliaryCarry().Return(false)
+		cndFlags.EXPECT().IsCarry().Return(true)
+		expectUpdateFlags(cndFlags, 0x11, 0x60, 0x71)
+		cndFlags.EXPECT().SetCarry()
 
 		alu := &ALUImpl{
 			A:              memory.NewRegister(0x11),
@@ -498,12 +500,8 @@ func TestALUImpl_CompareAccumulator(t *testing.T) {
 
 	t.Run("ALU == Operand", func(t *testing.T) {
 		cndFlags := alumock.NewMockConditionFlags(ctrl)
-		cndFlags.EXPECT().UpdateSign(uint8(0))
-		cndFlags.EXPECT().UpdateParity(uint8(0))
-		cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(0xA), uint8(0))
-		cndFlags.EXPECT().ClearZero()
+		expectUpdateFlags(cndFlags, 0xA, 0xF5, 0x100) // 0xA + ^0xA + 1
 		cndFlags.EXPECT().ClearCarry()
-		cndFlags.EXPECT().SetZero()
 
 		alu := &ALUImpl{
 			A:              memory.NewRegister(0xA),
@@ -515,11 +513,7 @@ func TestALUImpl_CompareAccumulator(t *testing.T) {
 
 	t.Run("ALU < Operand", func(t *testing.T) {
 		cndFlags := alumock.NewMockConditionFlags(ctrl)
-		cndFlags.EXPECT().UpdateSign(uint8(0xFF)) // Two's complement of -1 = 0xFF
-		cndFlags.EXPECT().UpdateParity(uint8(0xFF))
-		cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(0x9), uint8(0xFF))
-		cndFlags.EXPECT().ClearZero()
-		cndFlags.EXPECT().ClearCarry()
+		expectUpdateFlags(cndFlags, 0x9, 0xF5, 0xFF) // Two's complement of -1 = 0xFF, with no carry out
 		cndFlags.EXPECT().SetCarry()
 
 		alu := &ALUImpl{
@@ -532,10 +526,7 @@ func TestALUImpl_CompareAccumulator(t *testing.T) {
 
 	t.Run("ALU > Operand", func(t *testing.T) {
 		cndFlags := alumock.NewMockConditionFlags(ctrl)
-		cndFlags.EXPECT().UpdateSign(uint8(0x1)) // Two's complement of -1 = 0xFF
-		cndFlags.EXPECT().UpdateParity(uint8(0x1))
-		cndFlags.EXPECT().UpdateAuxiliaryCarry(uint8(0xA), uint8(0x1))
-		cndFlags.EXPECT().ClearZero()
+		expectUpdateFlags(cndFlags, 0xA, 0xF6, 0x101) // 0xA + ^0x9 + 1
 		cndFlags.EXPECT().ClearCarry()
 
 		alu := &ALUImpl{
@@ -547,3 +538,186 @@ func TestALUImpl_CompareAccumulator(t *testing.T) {
 	})
 
 }
+
+// referenceFlags is the expected outcome of an 8-bit operation, computed independently of the adder model used by
+// ALUImpl.
+type referenceFlags struct {
+	result   uint8
+	carry    bool
+	auxCarry bool
+}
+
+func newFlagsALU(a uint8, carry bool) *ALUImpl {
+	alu := NewALU(memory.NewRegister(a))
+	if carry {
+		alu.SetCarry()
+	}
+	return alu
+}
+
+func checkFlags(t *testing.T, name string, alu *ALUImpl, a uint8, b uint8, carry bool, expected referenceFlags, result uint8) {
+	t.Helper()
+
+	if result != expected.result {
+		t.Fatalf("%s 0x%02X, 0x%02X, CY=%v: expected result 0x%02X but got 0x%02X", name, a, b, carry, expected.result, result)
+	}
+	if alu.IsCarry() != expected.carry {
+		t.Fatalf("%s 0x%02X, 0x%02X, CY=%v: expected CY=%v but got %v", name, a, b, carry, expected.carry, alu.IsCarry())
+	}
+	if alu.IsAuxiliaryCarry() != expected.auxCarry {
+		t.Fatalf("%s 0x%02X, 0x%02X, CY=%v: expected AC=%v but got %v", name, a, b, carry, expected.auxCarry, alu.IsAuxiliaryCarry())
+	}
+	if alu.IsZero() != (expected.result == 0) || alu.IsSign() != (expected.result&0x80 > 0) {
+		t.Fatalf("%s 0x%02X, 0x%02X, CY=%v: Z or S does not match result 0x%02X", name, a, b, carry, expected.result)
+	}
+}
+
+func TestALUImpl_ArithmeticFlagsExhaustive(t *testing.T) {
+	var operations = []struct {
+		name      string
+		execute   func(alu *ALUImpl, b uint8)
+		reference func(a uint8, b uint8, carry bool) referenceFlags
+		stores    bool
+	}{
+		{"ADD", (*ALUImpl).AddImmediate, func(a, b uint8, carry bool) referenceFlags {
+			return addReference(a, b, 0)
+		}, true},
+		{"ADC", (*ALUImpl).AddImmediateWithCarry, func(a, b uint8, carry bool) referenceFlags {
+			return addReference(a, b, boolToInt(carry))
+		}, true},
+		{"SUB", (*ALUImpl).SubImmediate, func(a, b uint8, carry bool) referenceFlags {
+			return subReference(a, b, 0)
+		}, true},
+		{"SBB", (*ALUImpl).SubImmediateWithBorrow, func(a, b uint8, carry bool) referenceFlags {
+			return subReference(a, b, boolToInt(carry))
+		}, true},
+		{"CMP", (*ALUImpl).CompareAccumulator, func(a, b uint8, carry bool) referenceFlags {
+			return subReference(a, b, 0)
+		}, false},
+		{"ANA", (*ALUImpl).AndAccumulator, func(a, b uint8, carry bool) referenceFlags {
+			return referenceFlags{result: a & b, auxCarry: (a|b)&0x08 != 0}
+		}, true},
+		{"ORA", (*ALUImpl).OrAccumulator, func(a, b uint8, carry bool) referenceFlags {
+			return referenceFlags{result: a | b}
+		}, true},
+		{"XRA", (*ALUImpl).XOrAccumulator, func(a, b uint8, carry bool) referenceFlags {
+			return referenceFlags{result: a ^ b}
+		}, true},
+	}
+
+	for _, op := range operations {
+		for a := 0; a < 256; a++ {
+			for b := 0; b < 256; b++ {
+				for _, carry := range []bool{false, true} {
+					alu := newFlagsALU(uint8(a), carry)
+					op.execute(alu, uint8(b))
+
+					var result uint8
+					alu.GetA().Read8(&result)
+
+					expected := op.reference(uint8(a), uint8(b), carry)
+					if !op.stores {
+						// CMP leaves the accumulator unchanged; its flags describe the difference
+						if result != uint8(a) {
+							t.Fatalf("%s modified the accumulator", op.name)
+						}
+						result = expected.result
+					}
+					checkFlags(t, op.name, alu, uint8(a), uint8(b), carry, expected, result)
+				}
+			}
+		}
+	}
+}
+
+func TestALUImpl_IncrementDecrementFlagsExhaustive(t *testing.T) {
+	for v := 0; v < 256; v++ {
+		for _, carry := range []bool{false, true} {
+			alu := newFlagsALU(0, carry)
+			result := alu.Increment(uint8(v))
+			expected := addReference(uint8(v), 1, 0)
+			expected.carry = carry // INR does not affect CY
+			checkFlags(t, "INR", alu, uint8(v), 1, carry, expected, result)
+
+			alu = newFlagsALU(0, carry)
+			result = alu.Decrement(uint8(v))
+			expected = subReference(uint8(v), 1, 0)
+			expected.carry = carry // DCR does not affect CY
+			checkFlags(t, "DCR", alu, uint8(v), 1, carry, expected, result)
+		}
+	}
+}
+
+// TestALUImpl_DecimalAdjustAccumulatorExhaustive adds every pair of two-digit BCD numbers, with and without a
+// carry in, and checks that DAA produces the BCD sum and a decimal carry.
+func TestALUImpl_DecimalAdjustAccumulatorExhaustive(t *testing.T) {
+	toBCD := func(n int) uint8 { return uint8(n/10<<4 | n%10) }
+
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 100; y++ {
+			for _, carry := range []bool{false, true} {
+				alu := newFlagsALU(toBCD(x), carry)
+				alu.AddImmediateWithCarry(toBCD(y))
+				alu.DecimalAdjustAccumulator()
+
+				sum := x + y + boolToInt(carry)
+
+				var a uint8
+				alu.GetA().Read8(&a)
+				if a != toBCD(sum%100) || alu.IsCarry() != (sum >= 100) {
+					t.Fatalf("%02d + %02d + %v: expected %02X, CY=%v but got %02X, CY=%v", x, y, carry,
+						toBCD(sum%100), sum >= 100, a, alu.IsCarry())
+				}
+			}
+		}
+	}
+}
+
+func TestALUImpl_DecimalAdjustAccumulatorAuxiliaryCarry(t *testing.T) {
+	alu := newFlagsALU(0x9B, false)
+	alu.DecimalAdjustAccumulator()
+
+	var a uint8
+	alu.GetA().Read8(&a)
+	if a != 0x01 || !alu.IsCarry() || !alu.IsAuxiliaryCarry() {
+		t.Errorf("Expected DAA of 0x9B to give 0x01 with CY and AC set but got 0x%02X, CY=%v, AC=%v", a, alu.IsCarry(), alu.IsAuxiliaryCarry())
+	}
+}
+
+func TestALUImpl_SubImmediateWithBorrowFF(t *testing.T) {
+	alu := newFlagsALU(0x00, true)
+	alu.SubImmediateWithBorrow(0xFF)
+
+	var a uint8
+	alu.GetA().Read8(&a)
+	if a != 0x00 || !alu.IsCarry() {
+		t.Errorf("Expected 0x00 - 0xFF - 1 to give 0x00 with a borrow but got 0x%02X, CY=%v", a, alu.IsCarry())
+	}
+}
+
+func addReference(a uint8, b uint8, carry int) referenceFlags {
+	sum := int(a) + int(b) + carry
+	return referenceFlags{
+		result:   uint8(sum),
+		carry:    sum > 0xFF,
+		auxCarry: int(a&0x0F)+int(b&0x0F)+carry > 0x0F,
+	}
+}
+
+// subReference computes a - b - borrow. CY records a borrow out of bit 7, and AC is set unless the low-order four
+// bits borrowed.
+func subReference(a uint8, b uint8, borrow int) referenceFlags {
+	difference := int(a) - int(b) - borrow
+	return referenceFlags{
+		result:   uint8(difference),
+		carry:    difference < 0,
+		auxCarry: int(a&0x0F)-int(b&0x0F)-borrow >= 0,
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -23,7 +23,7 @@ type ConditionFlags interface {
 	UpdateCarryDoublePrecision(result uint32) bool
 	ClearCarry()
 	IsAuxiliaryCarry() bool
-	UpdateAuxiliaryCarry(original uint8, result uint8) bool
+	UpdateAuxiliaryCarry(operand1 uint8, operand2 uint8, result uint8) bool
 	SetAuxiliaryCarry()
 	ClearAuxiliaryCarry()
 }
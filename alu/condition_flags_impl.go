@@ -3,8 +3,7 @@ package alu
 const (
 	parityMask      byte   = 0x01
 	signMask        byte   = 0x80
-	auxCarryMask1   uint8  = 0x08
-	auxCarryMask2   uint8  = 0x10
+	auxCarryMask    uint8  = 0x10
 	carryMask       uint8  = 0x80
 	doubleCarryMask uint16 = 0x8000
 )
@@ -37,35 +36,51 @@ func (flags *ConditionFlagsImpl) ClearFlags() {
  *
  * See: page 4-13 of Intel 8080 User Manual
  */
+const (
+	statusCarry    uint8 = 0x01
+	statusParity   uint8 = 0x04
+	statusAuxCarry uint8 = 0x10
+	statusZero     uint8 = 0x40
+	statusSign     uint8 = 0x80
+
+	// statusFixed holds the bits that read as 1 regardless of the flags. Bits 3 and 5 always read as 0.
+	statusFixed uint8 = 0x02
+
+	// StatusWordFlags masks the bits of a status word that are backed by a flag. POP PSW of any byte b
+	// followed by PUSH PSW stores b&StatusWordFlags | 0x02.
+	StatusWordFlags = statusCarry | statusParity | statusAuxCarry | statusZero | statusSign
+)
 
-// CreateStatusWord generates an 8-bit status word from the flags' values.
+// CreateStatusWord generates an 8-bit status word from the flags' values. Bit 1 is always set and bits 3 and 5
+// are always clear, as on the 8080.
 func (flags *ConditionFlagsImpl) CreateStatusWord() uint8 {
-	var statusWord uint8 = 0x02
+	statusWord := statusFixed
 	if flags.Carry {
-		statusWord |= 0x01
+		statusWord |= statusCarry
 	}
 	if flags.Parity {
-		statusWord |= 0x04
+		statusWord |= statusParity
 	}
 	if flags.AuxillaryCarry {
-		statusWord |= 0x10
+		statusWord |= statusAuxCarry
 	}
 	if flags.Zero {
-		statusWord |= 0x40
+		statusWord |= statusZero
 	}
 	if flags.Sign {
-		statusWord |= 0x80
+		statusWord |= statusSign
 	}
 	return statusWord
 }
 
-// ApplyStatusWord updates the flags' values based on statusWord.
+// ApplyStatusWord updates the flags' values based on statusWord. Bits 1, 3 and 5 have no flag behind them and are
+// ignored.
 func (flags *ConditionFlagsImpl) ApplyStatusWord(statusWord uint8) {
-	flags.Carry = (statusWord & 0x01) > 0
-	flags.Parity = (statusWord & 0x04) > 0
-	flags.AuxillaryCarry = (statusWord & 0x10) > 0
-	flags.Zero = (statusWord & 0x40) > 0
-	flags.Sign = (statusWord & 0x80) > 0
+	flags.Carry = statusWord&statusCarry > 0
+	flags.Parity = statusWord&statusParity > 0
+	flags.AuxillaryCarry = statusWord&statusAuxCarry > 0
+	flags.Zero = statusWord&statusZero > 0
+	flags.Sign = statusWord&statusSign > 0
 }
 
 // IsZero returns the value of the Zero flag
@@ -179,13 +194,12 @@ func (flags *ConditionFlagsImpl) IsAuxiliaryCarry() bool {
 	return flags.AuxillaryCarry
 }
 
-// UpdateAuxiliaryCarry updates the Auxiliary Carry flag based on the value of the provided
-// result and returns the Auxiliary Carry flag. This flag is set when a carry occurs between
-// bits 3 and 4 of the low nibble.
-func (flags *ConditionFlagsImpl) UpdateAuxiliaryCarry(original uint8, result uint8) bool {
-	origBits := (0x18 & original) >> 3
-	newBits := (0x18 & result) >> 3
-	flags.AuxillaryCarry = origBits == 1 && newBits == 2
+// UpdateAuxiliaryCarry updates the Auxiliary Carry flag for the addition of operand1 and operand2 (plus any
+// carry in) that produced result, and returns the Auxiliary Carry flag. This flag is set when the addition
+// carries out of bit 3 into bit 4. The carry into bit 4 is recovered from the operands and result, whose bit 4
+// differs from the sum of the operands' bit 4 exactly when a carry arrived.
+func (flags *ConditionFlagsImpl) UpdateAuxiliaryCarry(operand1 uint8, operand2 uint8, result uint8) bool {
+	flags.AuxillaryCarry = (operand1^operand2^result)&auxCarryMask > 0
 	return flags.AuxillaryCarry
 }
 
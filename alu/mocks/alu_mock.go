@@ -515,17 +515,17 @@ func (mr *MockALUMockRecorder) SubImmediateWithBorrow(arg0 interface{}) *gomock.
 }
 
 // UpdateAuxiliaryCarry mocks base method.
-func (m *MockALU) UpdateAuxiliaryCarry(arg0, arg1 byte) bool {
+func (m *MockALU) UpdateAuxiliaryCarry(arg0, arg1, arg2 byte) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAuxiliaryCarry", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateAuxiliaryCarry", arg0, arg1, arg2)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // UpdateAuxiliaryCarry indicates an expected call of UpdateAuxiliaryCarry.
-func (mr *MockALUMockRecorder) UpdateAuxiliaryCarry(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockALUMockRecorder) UpdateAuxiliaryCarry(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuxiliaryCarry", reflect.TypeOf((*MockALU)(nil).UpdateAuxiliaryCarry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuxiliaryCarry", reflect.TypeOf((*MockALU)(nil).UpdateAuxiliaryCarry), arg0, arg1, arg2)
 }
 
 // UpdateCarry mocks base method.
@@ -557,15 +557,15 @@ func (mr *MockALUMockRecorder) UpdateCarryDoublePrecision(arg0 interface{}) *gom
 }
 
 // UpdateFlags mocks base method.
-func (m *MockALU) UpdateFlags(arg0 byte, arg1 uint16) {
+func (m *MockALU) UpdateFlags(arg0, arg1 byte, arg2 uint16) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "UpdateFlags", arg0, arg1)
+	m.ctrl.Call(m, "UpdateFlags", arg0, arg1, arg2)
 }
 
 // UpdateFlags indicates an expected call of UpdateFlags.
-func (mr *MockALUMockRecorder) UpdateFlags(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockALUMockRecorder) UpdateFlags(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlags", reflect.TypeOf((*MockALU)(nil).UpdateFlags), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFlags", reflect.TypeOf((*MockALU)(nil).UpdateFlags), arg0, arg1, arg2)
 }
 
 // UpdateFlagsExceptCarry mocks base method.
@@ -262,17 +262,17 @@ func (mr *MockConditionFlagsMockRecorder) SetZero() *gomock.Call {
 }
 
 // UpdateAuxiliaryCarry mocks base method.
-func (m *MockConditionFlags) UpdateAuxiliaryCarry(arg0, arg1 byte) bool {
+func (m *MockConditionFlags) UpdateAuxiliaryCarry(arg0, arg1, arg2 byte) bool {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAuxiliaryCarry", arg0, arg1)
+	ret := m.ctrl.Call(m, "UpdateAuxiliaryCarry", arg0, arg1, arg2)
 	ret0, _ := ret[0].(bool)
 	return ret0
 }
 
 // UpdateAuxiliaryCarry indicates an expected call of UpdateAuxiliaryCarry.
-func (mr *MockConditionFlagsMockRecorder) UpdateAuxiliaryCarry(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockConditionFlagsMockRecorder) UpdateAuxiliaryCarry(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuxiliaryCarry", reflect.TypeOf((*MockConditionFlags)(nil).UpdateAuxiliaryCarry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuxiliaryCarry", reflect.TypeOf((*MockConditionFlags)(nil).UpdateAuxiliaryCarry), arg0, arg1, arg2)
 }
 
 // UpdateCarry mocks base method.
@@ -0,0 +1,689 @@
+// Package asm is a two-pass Intel 8080 assembler accepting the source syntax of CP/M's ASM and MAC: an optional
+// label (followed by a colon, or starting in the first column), a mnemonic, directive or macro, operands separated by
+// commas, and a comment introduced by a semicolon. Names are case-insensitive. The directives are ORG, EQU, SET, DB,
+// DW, DS and END, and for conditional assembly, macros and source files:
+//
+//	IF expr ... [ELSE ...] ENDIF    assemble the first part if expr is nonzero and otherwise the second
+//	name MACRO [param,...] ... ENDM define a macro; a LOCAL statement at the start of its body lists labels
+//	                                renamed in each expansion, and EXITM ends an expansion early
+//	REPT count ... ENDM             repeat the body count times
+//	IRP param,<value,...> ... ENDM  repeat the body once for each value, substituted for param
+//	IRPC param,chars ... ENDM       repeat the body once for each character of chars
+//	INCLUDE file, MACLIB name       assemble the lines of file, or of the library name.LIB
+//
+// A macro's parameters are replaced by its arguments wherever they appear as names, and inside strings where they are
+// joined to an ampersand, which is removed: with N replaced by 1, LOOP&N becomes LOOP1 and 'PART &N' becomes 'PART 1'.
+// An argument in angle brackets may contain commas.
+//
+// Operands are expressions built from numbers (with an optional H, O, Q, B or D radix suffix), one- or
+// two-character strings, symbols, $ (the address of the current statement) and the operators
+//
+//	HIGH LOW unary + -       (highest precedence)
+//	* / MOD SHL SHR
+//	+ -
+//	EQ NE LT LE GT GE = <> < <= > >=
+//	NOT
+//	AND
+//	OR XOR                   (lowest precedence)
+//
+// Arithmetic is 16-bit and wraps. The registers B, C, D, E, H, L, M, A, SP and PSW are predefined symbols.
+package asm
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// Line is a line of the assembly listing.
+type Line struct {
+	File      string
+	Number    int
+	Expansion bool   // The line was generated by a macro, REPT or IRP
+	Address   uint16 // Location counter at the start of the statement
+	Field     string // The address of the statement, or = and the value of an EQU or SET, in hexadecimal
+	Bytes     []byte // The bytes the statement assembled to
+	Source    string
+}
+
+// Program is the result of assembling a source file.
+type Program struct {
+	Origin   uint16 // Lowest address assembled to
+	Code     []byte // Bytes from Origin through the highest address assembled to or reserved; gaps and DS are zeros
+	Entry    uint16 // Operand of END, if HasEntry is set
+	HasEntry bool
+	Symbols  map[string]uint16
+	Lines    []Line
+}
+
+// Error is an error at a line of source.
+type Error struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorList is the list of errors found by Assemble.
+type ErrorList []*Error
+
+func (list ErrorList) Error() string {
+	switch len(list) {
+	case 0:
+		return "no errors"
+	case 1:
+		return list[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", list[0], len(list)-1)
+}
+
+// maxErrors is the number of errors after which assembly stops.
+const maxErrors = 100
+
+// errUndefined is returned by a symbol lookup that refers to a symbol not yet defined.
+var errUndefined = errors.New("undefined symbol")
+
+// symbol is an entry in the symbol table.
+type symbol struct {
+	value    uint16
+	variable bool // Defined by SET, and so may be redefined
+	pass     int  // Pass in which the symbol was last defined
+}
+
+// sourceLine is a line of source, read from a file or generated by a macro, REPT or IRP expansion. Generated lines
+// carry the file and line number of the statement that expanded them.
+type sourceLine struct {
+	file      string
+	number    int
+	text      string
+	expansion bool
+}
+
+// assembler holds the state of an assembly.
+type assembler struct {
+	pass     int
+	pc       uint16
+	symbols  map[string]*symbol
+	image    memory.RAM
+	low      int
+	high     int
+	entry    uint16
+	hasEntry bool
+	ended    bool
+	wrapped  bool // The location counter has passed 0xFFFF since the last ORG
+	overflow bool // A location counter overflow has been reported
+	listing  []*Line
+	line     *Line      // Listing line of the current statement
+	source   sourceLine // The current statement
+	errs     ErrorList
+
+	macros     map[string]*macro
+	definition *definition // Macro, REPT or IRP whose body is being collected
+	conditions []condition // Enclosing IFs, innermost last
+	depth      int         // Nesting of INCLUDE files and expansions
+	expanding  int         // Nesting of expansions within the current file
+	exiting    bool        // EXITM was assembled, and the innermost expansion is ending
+	locals     int         // Number of LOCAL names generated
+}
+
+// Assemble assembles source, which is named name in error messages and the listing. INCLUDE and MACLIB files are
+// read relative to the directory of name.
+func Assemble(name string, source []byte) (*Program, error) {
+	a := &assembler{symbols: make(map[string]*symbol)}
+
+	for a.pass = 1; a.pass <= 2; a.pass++ {
+		a.pc, a.ended, a.hasEntry = 0, false, false
+		a.wrapped, a.overflow = false, false
+		a.image, a.low, a.high = memory.NewRAM(), memory.AddressSpaceSize, -1
+		a.listing = nil
+		a.macros, a.definition, a.conditions, a.locals = make(map[string]*macro), nil, nil, 0
+		a.expanding, a.exiting = 0, false
+
+		a.run(readLines(name, source))
+		if a.definition != nil {
+			a.errorf(a.definition.start, "%s without ENDM", a.definition.op)
+		}
+		if len(a.conditions) > 0 {
+			a.errorf(a.conditions[len(a.conditions)-1].start, "IF without ENDIF")
+		}
+		if len(a.errs) > 0 {
+			return nil, a.errs
+		}
+	}
+
+	program := &Program{Entry: a.entry, HasEntry: a.hasEntry, Symbols: make(map[string]uint16)}
+	for _, line := range a.listing {
+		program.Lines = append(program.Lines, *line)
+	}
+	if a.high >= a.low {
+		program.Origin = uint16(a.low)
+		program.Code = append([]byte(nil), a.image[a.low:a.high+1]...)
+	}
+	for name, sym := range a.symbols {
+		program.Symbols[name] = sym.value
+	}
+	return program, nil
+}
+
+// readLines splits source, read from the file name, into lines.
+func readLines(name string, source []byte) []sourceLine {
+	var lines []sourceLine
+	scanner := bufio.NewScanner(bytes.NewReader(source))
+	for number := 1; scanner.Scan(); number++ {
+		lines = append(lines, sourceLine{file: name, number: number, text: strings.TrimRight(scanner.Text(), "\r")})
+	}
+	return lines
+}
+
+// run assembles each line in turn until END or EXITM.
+func (a *assembler) run(lines []sourceLine) {
+	for _, source := range lines {
+		if a.ended || a.exiting || len(a.errs) >= maxErrors {
+			return
+		}
+
+		a.source = source
+		a.line = &Line{File: source.file, Number: source.number, Source: source.text, Expansion: source.expansion}
+		if a.pass == 2 {
+			a.listing = append(a.listing, a.line)
+		}
+		if err := a.statement(source.text); err != nil {
+			a.errorf(source, "%v", err)
+		}
+	}
+}
+
+// errorf records an error at a line of source.
+func (a *assembler) errorf(source sourceLine, format string, args ...interface{}) {
+	a.errs = append(a.errs, &Error{File: source.file, Line: source.number, Err: fmt.Errorf(format, args...)})
+}
+
+// isKeyword reports whether name is a mnemonic, directive or macro.
+func (a *assembler) isKeyword(name string) bool {
+	_, inst := instructions[name]
+	_, macro := a.macros[name]
+	return inst || macro || directives[name]
+}
+
+// directives are the assembler directives.
+var directives = map[string]bool{
+	"ORG": true, "EQU": true, "SET": true, "DB": true, "DW": true, "DS": true, "END": true,
+	"IF": true, "ELSE": true, "ENDIF": true, "MACRO": true, "ENDM": true, "EXITM": true, "LOCAL": true,
+	"REPT": true, "IRP": true, "IRPC": true, "INCLUDE": true, "MACLIB": true,
+}
+
+// splitLine splits a line of source into its label, operation and operand field. The label is the first name on
+// the line if it is followed by a colon, or if it starts in the first column and is not a mnemonic, directive or
+// macro.
+func (a *assembler) splitLine(text string) (label string, op string, operands string, err error) {
+	text = stripComment(text)
+	if strings.HasPrefix(text, "*") {
+		return "", "", "", nil
+	}
+
+	rest := strings.TrimLeft(text, " \t")
+	firstColumn := len(rest) == len(text)
+
+	name, after := scanName(rest)
+	if name == "" {
+		if strings.TrimSpace(rest) != "" {
+			return "", "", "", fmt.Errorf("unexpected %q", strings.TrimSpace(rest))
+		}
+		return "", "", "", nil
+	}
+
+	if strings.HasPrefix(after, ":") {
+		label, rest = name, after[1:]
+	} else if firstColumn && !a.isKeyword(strings.ToUpper(name)) {
+		label, rest = name, after
+	}
+	if label != "" {
+		name, after = scanName(strings.TrimLeft(rest, " \t"))
+		if name == "" && strings.TrimSpace(after) != "" {
+			return "", "", "", fmt.Errorf("unexpected %q", strings.TrimSpace(after))
+		}
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(label, "$", "")), strings.ToUpper(name), strings.TrimSpace(after), nil
+}
+
+// scanName returns the name at the start of s and the rest of s.
+func scanName(s string) (string, string) {
+	if s == "" || !isNameStart(s[0]) {
+		return "", s
+	}
+	i := 1
+	for i < len(s) && isNameChar(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// stripComment removes a comment, which starts at a semicolon outside a string.
+func stripComment(text string) string {
+	quoted := false
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\'':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				return text[:i]
+			}
+		}
+	}
+	return text
+}
+
+// statement assembles a line of source.
+func (a *assembler) statement(text string) error {
+	label, op, field, err := a.splitLine(text)
+	if a.definition != nil {
+		a.collect(op, text)
+		return nil
+	}
+	switch op {
+	case "IF", "ELSE", "ENDIF":
+		return a.conditional(op, field)
+	}
+	if a.skipping() {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	a.line.Address = a.pc
+	switch op {
+	case "MACRO":
+		return a.defineMacro(label, field)
+	case "REPT", "IRP", "IRPC":
+		if label != "" {
+			if err := a.define(label, a.pc, false); err != nil {
+				return err
+			}
+		}
+		return a.beginRepeat(op, field)
+	case "ENDM":
+		return fmt.Errorf("ENDM without MACRO, REPT or IRP")
+	case "EXITM":
+		if a.expanding == 0 {
+			return fmt.Errorf("EXITM outside a macro")
+		}
+		a.exiting = true
+		return nil
+	case "LOCAL":
+		return fmt.Errorf("LOCAL outside a macro")
+	case "INCLUDE", "MACLIB":
+		return a.include(op, field)
+	}
+	if m, ok := a.macros[op]; ok {
+		if label != "" {
+			if err := a.define(label, a.pc, false); err != nil {
+				return err
+			}
+			a.line.Field = fmt.Sprintf("%04X", a.pc)
+		}
+		return a.invoke(m, field)
+	}
+
+	tokens, err := tokenize(field)
+	if err != nil {
+		return err
+	}
+	operands := splitOperands(tokens)
+
+	switch op {
+	case "EQU", "SET":
+		if label == "" {
+			return fmt.Errorf("%s requires a label", op)
+		}
+		if len(operands) != 1 {
+			return fmt.Errorf("%s takes one operand", op)
+		}
+		v, err := a.value(operands[0])
+		if errors.Is(err, errUndefined) && a.pass == 1 {
+			return nil // Defined in pass 2
+		} else if err != nil {
+			return err
+		}
+		a.line.Field = fmt.Sprintf("=%04X", v)
+		return a.define(label, v, op == "SET")
+	}
+
+	if label != "" {
+		if err := a.define(label, a.pc, false); err != nil {
+			return err
+		}
+	}
+	if op == "" {
+		if label != "" {
+			a.line.Field = fmt.Sprintf("%04X", a.pc)
+		}
+		return nil
+	}
+
+	a.line.Field = fmt.Sprintf("%04X", a.pc)
+	if inst, ok := instructions[op]; ok {
+		return a.instruction(op, inst, operands)
+	}
+	return a.directive(op, operands)
+}
+
+// define defines a symbol. A label or EQU symbol may only be defined once, and must have the same value in both
+// passes.
+func (a *assembler) define(name string, v uint16, variable bool) error {
+	if _, ok := registers[name]; ok || operatorNames[name] || a.isKeyword(name) {
+		return fmt.Errorf("%s is reserved", name)
+	}
+
+	sym, ok := a.symbols[name]
+	switch {
+	case !ok:
+		a.symbols[name] = &symbol{value: v, variable: variable, pass: a.pass}
+		return nil
+	case sym.variable != variable:
+		return fmt.Errorf("%s redefined", name)
+	case variable:
+		sym.value, sym.pass = v, a.pass
+		return nil
+	case sym.pass == a.pass:
+		return fmt.Errorf("%s redefined", name)
+	case sym.value != v:
+		return fmt.Errorf("phase error: %s was 0x%04X in pass 1 but 0x%04X in pass 2", name, sym.value, v)
+	}
+	sym.pass = a.pass
+	return nil
+}
+
+// lookup returns the value of a symbol. In pass 1, a symbol that has not been defined yet returns errUndefined.
+func (a *assembler) lookup(name string) (uint16, error) {
+	if name == "$" {
+		return a.pc, nil
+	}
+	if v, ok := registers[name]; ok {
+		return v, nil
+	}
+
+	sym, ok := a.symbols[name]
+	if !ok && a.pass == 1 {
+		return 0, errUndefined
+	} else if !ok {
+		return 0, fmt.Errorf("undefined symbol %s", name)
+	}
+	return sym.value, nil
+}
+
+// value evaluates an expression.
+func (a *assembler) value(tokens []token) (uint16, error) {
+	return evaluate(tokens, a.lookup)
+}
+
+// operand evaluates an instruction operand. A forward reference evaluates to 0 in pass 1, since only the size of
+// the instruction matters then.
+func (a *assembler) operand(tokens []token) (uint16, error) {
+	v, err := a.value(tokens)
+	if errors.Is(err, errUndefined) {
+		return 0, nil
+	}
+	return v, err
+}
+
+// byteValue evaluates an operand that must fit in a byte. Negative values down to -256 are accepted.
+func (a *assembler) byteValue(tokens []token) (uint8, error) {
+	v, err := a.operand(tokens)
+	if err != nil {
+		return 0, err
+	}
+	if v > 0xFF && v < 0xFF00 {
+		return 0, fmt.Errorf("value 0x%04X does not fit in a byte", v)
+	}
+	return uint8(v), nil
+}
+
+// emit stores bytes at the location counter and advances it.
+func (a *assembler) emit(b ...uint8) {
+	for _, v := range b {
+		if a.pass == 2 && !a.wrapped {
+			a.image[a.pc] = v
+			a.line.Bytes = append(a.line.Bytes, v)
+		}
+		a.occupy(a.pc)
+		a.advance()
+	}
+}
+
+// advance increments the location counter past a byte of the program image.
+func (a *assembler) advance() {
+	a.pc++
+	if a.pc == 0 {
+		a.wrapped = true
+	}
+}
+
+// occupy extends the program image to include addr. A byte beyond 0xFFFF is reported as an error rather than
+// wrapping to 0x0000.
+func (a *assembler) occupy(addr uint16) {
+	if a.wrapped {
+		if !a.overflow {
+			a.errorf(a.source, "location counter overflow")
+			a.overflow = true
+		}
+		return
+	}
+	if int(addr) < a.low {
+		a.low = int(addr)
+	}
+	if int(addr) > a.high {
+		a.high = int(addr)
+	}
+}
+
+// register evaluates an operand naming a register (B, C, D, E, H, L, M or A).
+func (a *assembler) register(tokens []token) (uint8, error) {
+	v, err := a.operand(tokens)
+	if err != nil {
+		return 0, err
+	}
+	if v > 7 {
+		return 0, fmt.Errorf("invalid register")
+	}
+	return uint8(v), nil
+}
+
+// pair evaluates an operand naming a register pair (B, D, H, and SP or PSW). SP and PSW share an encoding, so the
+// one the instruction does not take, as in PUSH SP or INX PSW, is rejected by name.
+func (a *assembler) pair(op string, inst instruction, tokens []token) (uint8, error) {
+	if len(tokens) == 1 && tokens[0].kind == tokenName {
+		name := tokens[0].text
+		if name == "SP" && inst.form == formPairPSW || name == "PSW" && inst.form != formPairPSW {
+			return 0, fmt.Errorf("%s %s is not an instruction", op, name)
+		}
+	}
+
+	v, err := a.operand(tokens)
+	if err != nil {
+		return 0, err
+	}
+	if v > 7 || v&1 != 0 {
+		return 0, fmt.Errorf("invalid register pair")
+	}
+	return uint8(v), nil
+}
+
+// instruction assembles a machine instruction.
+func (a *assembler) instruction(op string, inst instruction, operands [][]token) error {
+	expected := map[form]int{
+		formNone: 0, formRegHigh: 1, formRegLow: 1, formMove: 2, formMoveImm: 2, formPair: 1, formPairPSW: 1,
+		formPairBD: 1, formPairImm: 2, formByte: 1, formWord: 1, formRestart: 1,
+	}[inst.form]
+	if len(operands) != expected {
+		return fmt.Errorf("%s takes %d operands but has %d", op, expected, len(operands))
+	}
+
+	// An invalid operand is reported, but the instruction still occupies its full length so that the addresses
+	// of the statements that follow are right
+	var err error
+	defer func() {
+		if err != nil {
+			a.pc += uint16(inst.length())
+		}
+	}()
+
+	switch inst.form {
+	case formNone:
+		a.emit(inst.opcode)
+	case formRegHigh, formMoveImm:
+		var r uint8
+		if r, err = a.register(operands[0]); err != nil {
+			return err
+		}
+		if inst.form == formRegHigh {
+			a.emit(inst.opcode | r<<3)
+			return nil
+		}
+		var data uint8
+		if data, err = a.byteValue(operands[1]); err != nil {
+			return err
+		}
+		a.emit(inst.opcode|r<<3, data)
+	case formRegLow:
+		var r uint8
+		if r, err = a.register(operands[0]); err != nil {
+			return err
+		}
+		a.emit(inst.opcode | r)
+	case formMove:
+		var dst, src uint8
+		if dst, err = a.register(operands[0]); err != nil {
+			return err
+		}
+		if src, err = a.register(operands[1]); err != nil {
+			return err
+		}
+		if dst == 6 && src == 6 {
+			err = fmt.Errorf("MOV M,M is not an instruction")
+			return err
+		}
+		a.emit(inst.opcode | dst<<3 | src)
+	case formPair, formPairPSW, formPairBD, formPairImm:
+		var rp uint8
+		if rp, err = a.pair(op, inst, operands[0]); err != nil {
+			return err
+		}
+		if inst.form == formPairBD && rp > 2 {
+			err = fmt.Errorf("%s takes register pair B or D", op)
+			return err
+		}
+		if inst.form != formPairImm {
+			a.emit(inst.opcode | rp<<3)
+			return nil
+		}
+		var data uint16
+		if data, err = a.operand(operands[1]); err != nil {
+			return err
+		}
+		a.emit(inst.opcode|rp<<3, uint8(data), uint8(data>>8))
+	case formByte:
+		var data uint8
+		if data, err = a.byteValue(operands[0]); err != nil {
+			return err
+		}
+		a.emit(inst.opcode, data)
+	case formWord:
+		var data uint16
+		if data, err = a.operand(operands[0]); err != nil {
+			return err
+		}
+		a.emit(inst.opcode, uint8(data), uint8(data>>8))
+	case formRestart:
+		var n uint16
+		if n, err = a.operand(operands[0]); err != nil {
+			return err
+		}
+		if n > 7 {
+			err = fmt.Errorf("invalid restart %d", n)
+			return err
+		}
+		a.emit(inst.opcode | uint8(n)<<3)
+	}
+	return nil
+}
+
+// directive assembles an assembler directive.
+func (a *assembler) directive(op string, operands [][]token) error {
+	switch op {
+	case "ORG", "DS":
+		if len(operands) != 1 {
+			return fmt.Errorf("%s takes one operand", op)
+		}
+		v, err := a.value(operands[0])
+		if errors.Is(err, errUndefined) {
+			return fmt.Errorf("%s operand must be defined before it is used", op)
+		} else if err != nil {
+			return err
+		}
+		if op == "ORG" {
+			a.pc, a.line.Address, a.wrapped = v, v, false
+			a.line.Field = fmt.Sprintf("%04X", v)
+			break
+		}
+		for ; v > 0; v-- {
+			a.occupy(a.pc)
+			a.advance()
+		}
+	case "DB":
+		if len(operands) == 0 {
+			return fmt.Errorf("DB requires an operand")
+		}
+		for _, operand := range operands {
+			if len(operand) == 1 && operand[0].kind == tokenString && len(operand[0].text) != 1 {
+				a.emit([]byte(operand[0].text)...)
+				continue
+			}
+			v, err := a.byteValue(operand)
+			if err != nil {
+				return err
+			}
+			a.emit(v)
+		}
+	case "DW":
+		if len(operands) == 0 {
+			return fmt.Errorf("DW requires an operand")
+		}
+		for _, operand := range operands {
+			v, err := a.operand(operand)
+			if err != nil {
+				return err
+			}
+			a.emit(uint8(v), uint8(v>>8))
+		}
+	case "END":
+		a.ended = true
+		a.line.Field = ""
+		if len(operands) > 1 {
+			return fmt.Errorf("END takes at most one operand")
+		}
+		if len(operands) == 1 {
+			v, err := a.operand(operands[0])
+			if err != nil {
+				return err
+			}
+			a.entry, a.hasEntry = v, true
+		}
+	default:
+		return fmt.Errorf("unknown instruction %s", op)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package asm
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/disasm"
+)
+
+// assemble assembles source and fails the test on an error.
+func assemble(t *testing.T, source string) *Program {
+	t.Helper()
+
+	program, err := Assemble("test.asm", []byte(source))
+	if err != nil {
+		t.Fatalf("Expected %q to assemble but got %v", source, err)
+	}
+	return program
+}
+
+func TestAssemble_Instructions(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected []byte
+	}{
+		{"NOP", []byte{0x00}},
+		{"hlt", []byte{0x76}},
+		{"MOV A,M", []byte{0x7E}},
+		{"MOV M,B", []byte{0x70}},
+		{"MVI C,0FEH", []byte{0x0E, 0xFE}},
+		{"MVI A,-1", []byte{0x3E, 0xFF}},
+		{"INR L", []byte{0x2C}},
+		{"DCR M", []byte{0x35}},
+		{"ADD B", []byte{0x80}},
+		{"CMP A", []byte{0xBF}},
+		{"LXI SP,1234H", []byte{0x31, 0x34, 0x12}},
+		{"LXI H,'AB'", []byte{0x21, 0x42, 0x41}},
+		{"PUSH PSW", []byte{0xF5}},
+		{"POP D", []byte{0xD1}},
+		{"DAD SP", []byte{0x39}},
+		{"STAX D", []byte{0x12}},
+		{"LDAX B", []byte{0x0A}},
+		{"ADI 'A'", []byte{0xC6, 0x41}},
+		{"OUT 10", []byte{0xD3, 0x0A}},
+		{"LDA 0BEEFH", []byte{0x3A, 0xEF, 0xBE}},
+		{"CALL 0", []byte{0xCD, 0x00, 0x00}},
+		{"JMP $", []byte{0xC3, 0x00, 0x00}},
+		{"RST 7", []byte{0xFF}},
+	}
+
+	for _, test := range tests {
+		program := assemble(t, "\t"+test.source)
+		if !bytes.Equal(program.Code, test.expected) {
+			t.Errorf("Expected %q to assemble to % X but got % X", test.source, test.expected, program.Code)
+		}
+	}
+}
+
+func TestAssemble_Expressions(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   uint16
+	}{
+		{"10", 10},
+		{"10D", 10},
+		{"10H", 0x10},
+		{"0FFFFH", 0xFFFF},
+		{"17O", 15},
+		{"17Q", 15},
+		{"1010B", 10},
+		{"1$000$000B", 0x40},
+		{"'A'", 0x41},
+		{"''''", 0x27},
+		{"'AB'", 0x4142},
+		{"1+2*3", 7},
+		{"(1+2)*3", 9},
+		{"7/2", 3},
+		{"7 MOD 2", 1},
+		{"0-1", 0xFFFF},
+		{"-1", 0xFFFF},
+		{"1 SHL 4", 0x10},
+		{"100H SHR 4", 0x10},
+		{"HIGH 1234H", 0x12},
+		{"LOW 1234H", 0x34},
+		{"HIGH 1234H+1", 0x13},
+		{"NOT 0", 0xFFFF},
+		{"0F0H AND 3CH", 0x30},
+		{"0F0H OR 0FH", 0xFF},
+		{"0FFH XOR 0FH", 0xF0},
+		{"1 EQ 1", 0xFFFF},
+		{"1 <> 1", 0},
+		{"1 < 2 AND 3 > 2", 0xFFFF},
+		{"2 LE 1", 0},
+		{"$+2", 0x102},
+	}
+
+	for _, test := range tests {
+		program := assemble(t, "\tORG\t100H\nX\tEQU\t"+test.expression)
+		if v := program.Symbols["X"]; v != test.expected {
+			t.Errorf("Expected %s to evaluate to 0x%04X but got 0x%04X", test.expression, test.expected, v)
+		}
+	}
+}
+
+func TestAssemble_Directives(t *testing.T) {
+	source := `
+; A comment line
+* Another comment line
+	ORG	200H
+START:	JMP	NEXT	; Forward reference
+COUNT	EQU	3
+N	SET	1
+N	SET	N+1
+TEXT:	DB	'Hi',0,'$',COUNT
+WORDS:	DW	START,-1
+	DS	2
+NEXT:	MVI	A,N
+	END	START
+	NOP		; Ignored after END
+`
+	program := assemble(t, source)
+
+	expected := []byte{0xC3, 0x0E, 0x02, 'H', 'i', 0x00, '$', 0x03, 0x00, 0x02, 0xFF, 0xFF, 0x00, 0x00, 0x3E, 0x02}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+	if program.Origin != 0x200 {
+		t.Errorf("Expected origin 0x0200 but got 0x%04X", program.Origin)
+	}
+	if !program.HasEntry || program.Entry != 0x200 {
+		t.Errorf("Expected entry 0x0200 but got 0x%04X (%v)", program.Entry, program.HasEntry)
+	}
+
+	symbols := map[string]uint16{"START": 0x200, "COUNT": 3, "N": 2, "TEXT": 0x203, "WORDS": 0x208, "NEXT": 0x20E}
+	for name, value := range symbols {
+		if program.Symbols[name] != value {
+			t.Errorf("Expected %s to be 0x%04X but got 0x%04X", name, value, program.Symbols[name])
+		}
+	}
+}
+
+func TestAssemble_Labels(t *testing.T) {
+	source := "FIRST\tNOP\n  SECOND: NOP\nthird:\nlast$name\tNOP\n"
+	program := assemble(t, source)
+
+	symbols := map[string]uint16{"FIRST": 0, "SECOND": 1, "THIRD": 2, "LASTNAME": 2}
+	for name, value := range symbols {
+		if v, ok := program.Symbols[name]; !ok || v != value {
+			t.Errorf("Expected %s to be 0x%04X but got 0x%04X (defined %v)", name, value, v, ok)
+		}
+	}
+}
+
+func TestAssemble_TopOfMemory(t *testing.T) {
+	program, err := Assemble("test.asm", []byte("\tORG\t0FFFEH\n\tDB\t1,2\nNEXT:\n"))
+	if err != nil {
+		t.Fatalf("Expected a program ending at 0xFFFF to assemble but got %v", err)
+	}
+	if program.Origin != 0xFFFE || !bytes.Equal(program.Code, []byte{1, 2}) || program.Symbols["NEXT"] != 0x0000 {
+		t.Errorf("Expected 01 02 at 0xFFFE and NEXT at 0x0000 but got % X at 0x%04X and NEXT at 0x%04X",
+			program.Code, program.Origin, program.Symbols["NEXT"])
+	}
+}
+
+func TestAssemble_Errors(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"\tFOO", "unknown instruction FOO"},
+		{"\tMOV A", "MOV takes 2 operands but has 1"},
+		{"\tMOV M,M", "MOV M,M is not an instruction"},
+		{"\tMVI A,100H", "does not fit in a byte"},
+		{"\tLXI C,0", "invalid register pair"},
+		{"\tSTAX H", "STAX takes register pair B or D"},
+		{"\tPUSH SP", "PUSH SP is not an instruction"},
+		{"\tPOP SP", "POP SP is not an instruction"},
+		{"\tLXI PSW,1234H", "LXI PSW is not an instruction"},
+		{"\tINX PSW", "INX PSW is not an instruction"},
+		{"\tDCX PSW", "DCX PSW is not an instruction"},
+		{"\tDAD PSW", "DAD PSW is not an instruction"},
+		{"\tRST 8", "invalid restart 8"},
+		{"\tJMP NOWHERE", "undefined symbol NOWHERE"},
+		{"X\tEQU\t1\nX\tEQU\t2", "X redefined"},
+		{"LOOP:\tNOP\nLOOP:\tNOP", "LOOP redefined"},
+		{"B:\tNOP", "B is reserved"},
+		{"\tEQU\t1", "EQU requires a label"},
+		{"MOV:\tNOP", "MOV is reserved"},
+		{"\tORG\tLATER\nLATER:", "ORG operand must be defined before it is used"},
+		{"\tDB\t'unterminated", "unterminated string"},
+		{"\tDB\t1/0", "division by zero"},
+		{"\tMVI\tA,(1", "missing )"},
+		{"\tORG\t0FFFFH\n\tDB\t1,2", "test.asm:2: location counter overflow"},
+		{"\tORG\t0FFFEH\n\tNOP\n\tLXI\tH,0", "test.asm:3: location counter overflow"},
+		{"\tORG\t0FFF0H\n\tDS\t20H", "test.asm:2: location counter overflow"},
+	}
+
+	for _, test := range tests {
+		_, err := Assemble("test.asm", []byte(test.source))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected %q to fail with %q but got %v", test.source, test.expected, err)
+		}
+	}
+}
+
+func TestAssemble_ErrorLocation(t *testing.T) {
+	_, err := Assemble("test.asm", []byte("\tNOP\n\tBAD\n\tNOP\n\tWORSE\n"))
+
+	var list ErrorList
+	if !errors.As(err, &list) {
+		t.Fatalf("Expected an ErrorList but got %v", err)
+	}
+	if len(list) != 2 || list[0].Line != 2 || list[1].Line != 4 || list[0].File != "test.asm" {
+		t.Errorf("Expected errors at lines 2 and 4 of test.asm but got %v", list)
+	}
+}
+
+// TestAssemble_CPUDiag assembles the diagnostic's source and compares it to the binary the emulator is tested with.
+func TestAssemble_CPUDiag(t *testing.T) {
+	source, err := ioutil.ReadFile("../cpudiag.asm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := ioutil.ReadFile("../cpudiag.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := Assemble("cpudiag.asm", source)
+	if err != nil {
+		t.Fatalf("Expected cpudiag.asm to assemble but got %v", err)
+	}
+	if program.Origin != 0x100 {
+		t.Errorf("Expected origin 0x0100 but got 0x%04X", program.Origin)
+	}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected cpudiag.asm to assemble to cpudiag.bin but the %d bytes assembled differ from its %d",
+			len(program.Code), len(expected))
+	}
+}
+
+// TestAssemble_DisassemblyRoundTrip reassembles the source written by the recursive disassembler.
+func TestAssemble_DisassemblyRoundTrip(t *testing.T) {
+	expected, err := ioutil.ReadFile("../cpudiag.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := disasm.Disassemble(expected, 0x100, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var source bytes.Buffer
+	if err := d.WriteSource(&source); err != nil {
+		t.Fatal(err)
+	}
+
+	program := assemble(t, source.String())
+	if program.Origin != 0x100 || !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected the disassembly to reassemble to cpudiag.bin")
+	}
+}
@@ -0,0 +1,400 @@
+package asm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// tokenKind identifies a lexical token of an operand field.
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenName
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenComma
+)
+
+type token struct {
+	kind  tokenKind
+	text  string // Upper-cased name or operator, or the contents of a string
+	value uint16 // Value of a number
+}
+
+// operatorNames are the operators written as words. They are reserved and cannot be used as symbols.
+var operatorNames = map[string]bool{
+	"NOT": true, "AND": true, "OR": true, "XOR": true, "MOD": true, "SHL": true, "SHR": true,
+	"EQ": true, "NE": true, "LT": true, "LE": true, "GT": true, "GE": true, "HIGH": true, "LOW": true,
+}
+
+// isNameStart reports whether c may begin a name.
+func isNameStart(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '?' || c == '@' || c == '_' || c == '.'
+}
+
+// isNameChar reports whether c may continue a name.
+func isNameChar(c byte) bool {
+	return isNameStart(c) || c >= '0' && c <= '9' || c == '$'
+}
+
+// tokenize splits an operand field into tokens. Names and operators are upper-cased; the contents of strings are not.
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '\'':
+			text, n, err := scanString(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text})
+			i += n
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			value, err := parseNumber(s[i:j])
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: value})
+			i = j
+		case isNameStart(c):
+			j := i
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			name := strings.ToUpper(strings.ReplaceAll(s[i:j], "$", ""))
+			kind := tokenName
+			if operatorNames[name] {
+				kind = tokenOperator
+			}
+			tokens = append(tokens, token{kind: kind, text: name})
+			i = j
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLeftParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRightParen, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ","})
+			i++
+		case c == '$':
+			tokens = append(tokens, token{kind: tokenName, text: "$"})
+			i++
+		default:
+			op := string(c)
+			if i+1 < len(s) {
+				if two := s[i : i+2]; two == "<=" || two == ">=" || two == "<>" {
+					op = two
+				}
+			}
+			if !strings.Contains("+-*/=<>", string(c)) {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// scanString scans the quoted string at the start of s, in which a doubled quote stands for a quote. It returns the
+// contents of the string and the number of bytes of s it occupied.
+func scanString(s string) (string, int, error) {
+	var text strings.Builder
+	for i := 1; i < len(s); i++ {
+		if s[i] != '\'' {
+			text.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '\'' {
+			text.WriteByte('\'')
+			i++
+			continue
+		}
+		return text.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+// parseNumber parses a number with an optional radix suffix: H for hexadecimal, O or Q for octal, B for binary and
+// D for decimal. A $ may be used to separate digits.
+func parseNumber(s string) (uint16, error) {
+	digits := strings.ToUpper(strings.ReplaceAll(s, "$", ""))
+	base := 10
+	switch digits[len(digits)-1] {
+	case 'H':
+		base = 16
+	case 'O', 'Q':
+		base = 8
+	case 'B':
+		base = 2
+	case 'D':
+		base = 10
+	}
+	if c := digits[len(digits)-1]; c < '0' || c > '9' {
+		digits = digits[:len(digits)-1]
+	}
+
+	v, err := strconv.ParseUint(digits, base, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return uint16(v), nil
+}
+
+// expression evaluates an expression from a token stream. Arithmetic is 16-bit and wraps; a relational operator
+// yields 0FFFFH for true and 0 for false.
+type expression struct {
+	tokens []token
+	pos    int
+	lookup func(name string) (uint16, error)
+}
+
+// evaluate evaluates tokens, which must form a single expression.
+func evaluate(tokens []token, lookup func(name string) (uint16, error)) (uint16, error) {
+	if len(tokens) == 0 {
+		return 0, fmt.Errorf("missing expression")
+	}
+
+	e := &expression{tokens: tokens, lookup: lookup}
+	v, err := e.or()
+	if err != nil {
+		return 0, err
+	}
+	if e.pos < len(e.tokens) {
+		return 0, fmt.Errorf("unexpected %q in expression", e.tokens[e.pos].text)
+	}
+	return v, nil
+}
+
+func (e *expression) peek() token {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos]
+	}
+	return token{kind: tokenEnd}
+}
+
+// operator consumes the next token if it is one of ops and returns it.
+func (e *expression) operator(ops ...string) (string, bool) {
+	t := e.peek()
+	if t.kind != tokenOperator {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			e.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (e *expression) or() (uint16, error) {
+	v, err := e.and()
+	for err == nil {
+		op, ok := e.operator("OR", "XOR")
+		if !ok {
+			break
+		}
+		var rhs uint16
+		if rhs, err = e.and(); op == "OR" {
+			v |= rhs
+		} else {
+			v ^= rhs
+		}
+	}
+	return v, err
+}
+
+func (e *expression) and() (uint16, error) {
+	v, err := e.not()
+	for err == nil {
+		if _, ok := e.operator("AND"); !ok {
+			break
+		}
+		var rhs uint16
+		rhs, err = e.not()
+		v &= rhs
+	}
+	return v, err
+}
+
+func (e *expression) not() (uint16, error) {
+	if _, ok := e.operator("NOT"); ok {
+		v, err := e.not()
+		return ^v, err
+	}
+	return e.relation()
+}
+
+func (e *expression) relation() (uint16, error) {
+	v, err := e.sum()
+	if err != nil {
+		return 0, err
+	}
+
+	op, ok := e.operator("EQ", "NE", "LT", "LE", "GT", "GE", "=", "<>", "<", "<=", ">", ">=")
+	if !ok {
+		return v, nil
+	}
+	rhs, err := e.sum()
+	if err != nil {
+		return 0, err
+	}
+
+	var result bool
+	switch op {
+	case "EQ", "=":
+		result = v == rhs
+	case "NE", "<>":
+		result = v != rhs
+	case "LT", "<":
+		result = v < rhs
+	case "LE", "<=":
+		result = v <= rhs
+	case "GT", ">":
+		result = v > rhs
+	case "GE", ">=":
+		result = v >= rhs
+	}
+	if result {
+		return 0xFFFF, nil
+	}
+	return 0, nil
+}
+
+func (e *expression) sum() (uint16, error) {
+	v, err := e.product()
+	for err == nil {
+		op, ok := e.operator("+", "-")
+		if !ok {
+			break
+		}
+		var rhs uint16
+		if rhs, err = e.product(); op == "+" {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+	return v, err
+}
+
+func (e *expression) product() (uint16, error) {
+	v, err := e.unary()
+	for err == nil {
+		op, ok := e.operator("*", "/", "MOD", "SHL", "SHR")
+		if !ok {
+			break
+		}
+		var rhs uint16
+		if rhs, err = e.unary(); err != nil {
+			break
+		}
+		switch op {
+		case "*":
+			v *= rhs
+		case "/", "MOD":
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			if op == "/" {
+				v /= rhs
+			} else {
+				v %= rhs
+			}
+		case "SHL":
+			v <<= rhs
+		case "SHR":
+			v >>= rhs
+		}
+	}
+	return v, err
+}
+
+func (e *expression) unary() (uint16, error) {
+	op, ok := e.operator("+", "-", "HIGH", "LOW")
+	if !ok {
+		return e.primary()
+	}
+
+	v, err := e.unary()
+	switch op {
+	case "-":
+		v = -v
+	case "HIGH":
+		v >>= 8
+	case "LOW":
+		v &= 0xFF
+	}
+	return v, err
+}
+
+func (e *expression) primary() (uint16, error) {
+	t := e.peek()
+	e.pos++
+
+	switch t.kind {
+	case tokenNumber:
+		return t.value, nil
+	case tokenString:
+		switch len(t.text) {
+		case 1:
+			return uint16(t.text[0]), nil
+		case 2:
+			return uint16(t.text[0])<<8 | uint16(t.text[1]), nil
+		}
+		return 0, fmt.Errorf("string '%s' is not a one or two character constant", t.text)
+	case tokenName:
+		return e.lookup(t.text)
+	case tokenLeftParen:
+		v, err := e.or()
+		if err != nil {
+			return 0, err
+		}
+		if e.peek().kind != tokenRightParen {
+			return 0, fmt.Errorf("missing )")
+		}
+		e.pos++
+		return v, nil
+	case tokenEnd:
+		return 0, fmt.Errorf("missing operand")
+	}
+	return 0, fmt.Errorf("unexpected %q in expression", t.text)
+}
+
+// splitOperands splits tokens at top-level commas.
+func splitOperands(tokens []token) [][]token {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	var operands [][]token
+	depth, start := 0, 0
+	for i, t := range tokens {
+		switch t.kind {
+		case tokenLeftParen:
+			depth++
+		case tokenRightParen:
+			depth--
+		case tokenComma:
+			if depth == 0 {
+				operands = append(operands, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(operands, tokens[start:])
+}
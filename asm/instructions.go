@@ -0,0 +1,81 @@
+package asm
+
+// form identifies the operands an instruction takes and how they are encoded.
+type form int
+
+const (
+	formNone    form = iota // No operands
+	formRegHigh             // A register in bits 3-5 (INR, DCR)
+	formRegLow              // A register in bits 0-2 (ADD, ..., CMP)
+	formMove                // Destination register in bits 3-5, source in bits 0-2 (MOV)
+	formMoveImm             // A register in bits 3-5, then a data byte (MVI)
+	formPair                // A register pair or SP in bits 4-5 (INX, DCX, DAD)
+	formPairPSW             // A register pair or PSW in bits 4-5 (PUSH, POP)
+	formPairBD              // Register pair B or D in bits 4-5 (STAX, LDAX)
+	formPairImm             // A register pair or SP in bits 4-5, then a data word (LXI)
+	formByte                // A data byte or port
+	formWord                // An address or data word
+	formRestart             // A restart number in bits 3-5 (RST)
+)
+
+type instruction struct {
+	opcode uint8
+	form   form
+}
+
+// instructions maps each 8080 mnemonic to its base opcode and operand form.
+var instructions = map[string]instruction{
+	"NOP": {0x00, formNone}, "RLC": {0x07, formNone}, "RRC": {0x0F, formNone}, "RAL": {0x17, formNone},
+	"RAR": {0x1F, formNone}, "DAA": {0x27, formNone}, "CMA": {0x2F, formNone}, "STC": {0x37, formNone},
+	"CMC": {0x3F, formNone}, "HLT": {0x76, formNone}, "RET": {0xC9, formNone}, "XTHL": {0xE3, formNone},
+	"PCHL": {0xE9, formNone}, "XCHG": {0xEB, formNone}, "DI": {0xF3, formNone}, "SPHL": {0xF9, formNone},
+	"EI": {0xFB, formNone},
+
+	"RNZ": {0xC0, formNone}, "RZ": {0xC8, formNone}, "RNC": {0xD0, formNone}, "RC": {0xD8, formNone},
+	"RPO": {0xE0, formNone}, "RPE": {0xE8, formNone}, "RP": {0xF0, formNone}, "RM": {0xF8, formNone},
+
+	"INR": {0x04, formRegHigh}, "DCR": {0x05, formRegHigh},
+
+	"ADD": {0x80, formRegLow}, "ADC": {0x88, formRegLow}, "SUB": {0x90, formRegLow}, "SBB": {0x98, formRegLow},
+	"ANA": {0xA0, formRegLow}, "XRA": {0xA8, formRegLow}, "ORA": {0xB0, formRegLow}, "CMP": {0xB8, formRegLow},
+
+	"MOV": {0x40, formMove},
+	"MVI": {0x06, formMoveImm},
+
+	"INX": {0x03, formPair}, "DCX": {0x0B, formPair}, "DAD": {0x09, formPair}, "PUSH": {0xC5, formPairPSW},
+	"POP": {0xC1, formPairPSW},
+
+	"STAX": {0x02, formPairBD}, "LDAX": {0x0A, formPairBD},
+
+	"LXI": {0x01, formPairImm},
+
+	"ADI": {0xC6, formByte}, "ACI": {0xCE, formByte}, "SUI": {0xD6, formByte}, "SBI": {0xDE, formByte},
+	"ANI": {0xE6, formByte}, "XRI": {0xEE, formByte}, "ORI": {0xF6, formByte}, "CPI": {0xFE, formByte},
+	"IN": {0xDB, formByte}, "OUT": {0xD3, formByte},
+
+	"SHLD": {0x22, formWord}, "LHLD": {0x2A, formWord}, "STA": {0x32, formWord}, "LDA": {0x3A, formWord},
+	"JMP": {0xC3, formWord}, "CALL": {0xCD, formWord},
+	"JNZ": {0xC2, formWord}, "JZ": {0xCA, formWord}, "JNC": {0xD2, formWord}, "JC": {0xDA, formWord},
+	"JPO": {0xE2, formWord}, "JPE": {0xEA, formWord}, "JP": {0xF2, formWord}, "JM": {0xFA, formWord},
+	"CNZ": {0xC4, formWord}, "CZ": {0xCC, formWord}, "CNC": {0xD4, formWord}, "CC": {0xDC, formWord},
+	"CPO": {0xE4, formWord}, "CPE": {0xEC, formWord}, "CP": {0xF4, formWord}, "CM": {0xFC, formWord},
+
+	"RST": {0xC7, formRestart},
+}
+
+// length returns the number of bytes the instruction assembles to.
+func (inst instruction) length() int {
+	switch inst.form {
+	case formMoveImm, formByte:
+		return 2
+	case formPairImm, formWord:
+		return 3
+	}
+	return 1
+}
+
+// registers are the predefined register symbols. Register pairs are named by their high-order register, and SP and
+// PSW share the encoding 6.
+var registers = map[string]uint16{
+	"B": 0, "C": 1, "D": 2, "E": 3, "H": 4, "L": 5, "M": 6, "A": 7, "SP": 6, "PSW": 6,
+}
@@ -0,0 +1,63 @@
+package asm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// listingBytesPerLine is the most bytes shown beside a line of the listing. A statement that assembles to more
+// continues on the following lines.
+const listingBytesPerLine = 4
+
+// WriteListing writes the assembly listing: the address, bytes and line number of every line of source, followed by
+// the symbol table. Lines generated by a macro, REPT or IRP are marked with a + after the line number of the
+// statement that expanded them.
+func (p *Program) WriteListing(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	for _, line := range p.Lines {
+		n := len(line.Bytes)
+		if n > listingBytesPerLine {
+			n = listingBytesPerLine
+		}
+		mark := " "
+		if line.Expansion {
+			mark = "+"
+		}
+		fmt.Fprintf(out, "%-5s  %-8X  %5d%s %s\n", line.Field, line.Bytes[:n], line.Number, mark, line.Source)
+
+		for i := n; i < len(line.Bytes); i += listingBytesPerLine {
+			end := i + listingBytesPerLine
+			if end > len(line.Bytes) {
+				end = len(line.Bytes)
+			}
+			fmt.Fprintf(out, "%04X   %X\n", line.Address+uint16(i), line.Bytes[i:end])
+		}
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "SYMBOLS")
+	p.writeSymbols(out)
+	return out.Flush()
+}
+
+// WriteSymbols writes the symbol table, one symbol per line with its value in hexadecimal, sorted by name.
+func (p *Program) WriteSymbols(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	p.writeSymbols(out)
+	return out.Flush()
+}
+
+func (p *Program) writeSymbols(w io.Writer) {
+	names := make([]string, 0, len(p.Symbols))
+	for name := range p.Symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "%04X %s\n", p.Symbols[name], name)
+	}
+}
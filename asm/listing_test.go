@@ -0,0 +1,58 @@
+package asm
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestProgram_WriteListing(t *testing.T) {
+	program := assemble(t, "\tORG\t100H\nFIVE\tEQU\t5\nSTART:\tMVI\tA,FIVE\n\tDB\t'HELLO'\n\tEND\n")
+
+	var out bytes.Buffer
+	if err := program.WriteListing(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "0100                 1  \tORG\t100H\n" +
+		"=0005                2  FIVE\tEQU\t5\n" +
+		"0100   3E05          3  START:\tMVI\tA,FIVE\n" +
+		"0102   48454C4C      4  \tDB\t'HELLO'\n" +
+		"0106   4F\n" +
+		"                     5  \tEND\n" +
+		"\n" +
+		"SYMBOLS\n" +
+		"0005 FIVE\n" +
+		"0100 START\n"
+	if out.String() != expected {
+		t.Errorf("Expected listing\n%s\nbut got\n%s", expected, out.String())
+	}
+}
+
+func TestProgram_WriteListingExpansion(t *testing.T) {
+	program := assemble(t, "TWICE\tMACRO\tX\n\tDB\tX,X\n\tENDM\n\tTWICE\t7\n")
+
+	var out bytes.Buffer
+	if err := program.WriteListing(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "0000   0707          4+ \tDB\t7,7\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Expected the listing to contain %q but got\n%s", expected, out.String())
+	}
+}
+
+func TestProgram_WriteSymbols(t *testing.T) {
+	program := assemble(t, "ZED\tEQU\t1\nALPHA\tEQU\t0FFFFH\n")
+
+	var out bytes.Buffer
+	if err := program.WriteSymbols(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "FFFF ALPHA\n0001 ZED\n"
+	if out.String() != expected {
+		t.Errorf("Expected symbols\n%s\nbut got\n%s", expected, out.String())
+	}
+}
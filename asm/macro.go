@@ -0,0 +1,384 @@
+package asm
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxDepth is the deepest that INCLUDE files and macro expansions may nest, which stops a macro that invokes itself
+// unconditionally.
+const maxDepth = 64
+
+// macro is a macro defined with MACRO.
+type macro struct {
+	name   string
+	params []string
+	body   []string
+}
+
+// definition is a MACRO, REPT, IRP or IRPC whose body is being collected up to its ENDM.
+type definition struct {
+	op     string
+	start  sourceLine
+	macro  *macro   // The macro being defined, for MACRO
+	count  uint16   // Number of repetitions, for REPT
+	param  string   // Parameter, for IRP and IRPC
+	values []string // Values of the parameter, for IRP and IRPC
+	body   []string
+	nested int // Number of enclosed MACRO, REPT, IRP and IRPC blocks still open
+}
+
+// condition is an IF being assembled.
+type condition struct {
+	start     sourceLine
+	active    bool // Statements are being assembled
+	enclosing bool // The enclosing statements are being assembled
+	seenElse  bool
+}
+
+// skipping reports whether statements are being skipped by a false IF or its ELSE.
+func (a *assembler) skipping() bool {
+	return len(a.conditions) > 0 && !a.conditions[len(a.conditions)-1].active
+}
+
+// conditional assembles IF, ELSE and ENDIF. An IF whose operand is nonzero assembles the statements up to its ELSE
+// or ENDIF; otherwise it assembles the statements between its ELSE and ENDIF.
+func (a *assembler) conditional(op string, field string) error {
+	switch op {
+	case "IF":
+		if a.skipping() {
+			a.conditions = append(a.conditions, condition{start: a.source})
+			return nil
+		}
+
+		tokens, err := tokenize(field)
+		if err != nil {
+			return err
+		}
+		v, err := a.value(tokens)
+		if errors.Is(err, errUndefined) {
+			return fmt.Errorf("IF operand must be defined before it is used")
+		} else if err != nil {
+			return err
+		}
+		a.conditions = append(a.conditions, condition{start: a.source, active: v != 0, enclosing: true})
+	case "ELSE":
+		if len(a.conditions) == 0 {
+			return fmt.Errorf("ELSE without IF")
+		}
+		c := &a.conditions[len(a.conditions)-1]
+		if c.seenElse {
+			return fmt.Errorf("ELSE repeated")
+		}
+		c.active, c.seenElse = c.enclosing && !c.active, true
+	case "ENDIF":
+		if len(a.conditions) == 0 {
+			return fmt.Errorf("ENDIF without IF")
+		}
+		a.conditions = a.conditions[:len(a.conditions)-1]
+	}
+	return nil
+}
+
+// defineMacro starts the definition of a macro, whose parameters are listed in field.
+func (a *assembler) defineMacro(name string, field string) error {
+	if name == "" {
+		return fmt.Errorf("MACRO requires a name")
+	}
+	if _, ok := registers[name]; ok || operatorNames[name] || a.isKeyword(name) {
+		return fmt.Errorf("%s is reserved", name)
+	}
+
+	m := &macro{name: name}
+	for _, param := range splitArguments(field) {
+		if p, rest := scanName(param); p == "" || rest != "" {
+			return fmt.Errorf("invalid macro parameter %q", param)
+		}
+		m.params = append(m.params, strings.ToUpper(param))
+	}
+	a.definition = &definition{op: "MACRO", start: a.source, macro: m}
+	return nil
+}
+
+// beginRepeat starts the body of a REPT, IRP or IRPC.
+func (a *assembler) beginRepeat(op string, field string) error {
+	d := &definition{op: op, start: a.source}
+
+	if op == "REPT" {
+		tokens, err := tokenize(field)
+		if err != nil {
+			return err
+		}
+		if d.count, err = a.value(tokens); errors.Is(err, errUndefined) {
+			return fmt.Errorf("REPT operand must be defined before it is used")
+		} else if err != nil {
+			return err
+		}
+		a.definition = d
+		return nil
+	}
+
+	args := splitArguments(field)
+	if len(args) != 2 {
+		return fmt.Errorf("%s takes a parameter and a list", op)
+	}
+	if p, rest := scanName(args[0]); p == "" || rest != "" {
+		return fmt.Errorf("invalid %s parameter %q", op, args[0])
+	}
+	d.param = strings.ToUpper(args[0])
+	if op == "IRP" {
+		d.values = splitArguments(args[1])
+	} else {
+		for _, c := range args[1] {
+			d.values = append(d.values, string(c))
+		}
+	}
+	a.definition = d
+	return nil
+}
+
+// collect adds a line to the body being defined. The ENDM that matches the definition ends it: a macro is then
+// ready to be invoked, and a REPT, IRP or IRPC is expanded.
+func (a *assembler) collect(op string, text string) {
+	d := a.definition
+	switch op {
+	case "MACRO", "REPT", "IRP", "IRPC":
+		d.nested++
+	case "ENDM":
+		if d.nested > 0 {
+			d.nested--
+			break
+		}
+
+		a.definition = nil
+		ending := a.source
+		a.source = d.start
+		defer func() { a.source = ending }()
+
+		switch d.op {
+		case "MACRO":
+			d.macro.body = d.body
+			a.macros[d.macro.name] = d.macro
+		case "REPT":
+			for i := uint16(0); i < d.count && !a.exiting; i++ {
+				a.expand(d.body, nil)
+			}
+		default:
+			for _, value := range d.values {
+				if a.exiting {
+					break
+				}
+				a.expand(d.body, map[string]string{d.param: value})
+			}
+		}
+		a.exiting = false
+		return
+	}
+	d.body = append(d.body, text)
+}
+
+// invoke expands a macro, substituting the arguments in field for its parameters. Missing arguments are empty.
+func (a *assembler) invoke(m *macro, field string) error {
+	args := splitArguments(field)
+	if len(args) > len(m.params) {
+		return fmt.Errorf("%s takes %d arguments but has %d", m.name, len(m.params), len(args))
+	}
+
+	values := make(map[string]string)
+	for i, param := range m.params {
+		if i < len(args) {
+			values[param] = args[i]
+		} else {
+			values[param] = ""
+		}
+	}
+	a.expand(m.body, values)
+	a.exiting = false
+	return nil
+}
+
+// expand assembles a body with values substituted for its parameters. The names listed by LOCAL statements at the
+// start of the body are replaced with names unique to this expansion.
+func (a *assembler) expand(body []string, values map[string]string) {
+	invocation := a.source
+	if a.depth >= maxDepth {
+		a.errorf(invocation, "macros nested more than %d deep", maxDepth)
+		return
+	}
+
+	for len(body) > 0 {
+		_, op, field, _ := a.splitLine(body[0])
+		if op != "LOCAL" {
+			break
+		}
+		if values == nil {
+			values = make(map[string]string)
+		}
+		for _, name := range splitArguments(field) {
+			a.locals++
+			values[strings.ToUpper(name)] = fmt.Sprintf("??%04d", a.locals)
+		}
+		body = body[1:]
+	}
+
+	lines := make([]sourceLine, len(body))
+	for i, text := range body {
+		lines[i] = sourceLine{file: invocation.file, number: invocation.number, text: substitute(text, values),
+			expansion: true}
+	}
+
+	conditions := len(a.conditions)
+	a.depth++
+	a.expanding++
+	a.run(lines)
+	a.expanding--
+	a.depth--
+	if len(a.conditions) > conditions && !a.exiting {
+		a.errorf(invocation, "IF without ENDIF in expansion")
+	}
+	if len(a.conditions) > conditions {
+		a.conditions = a.conditions[:conditions]
+	}
+	a.source = invocation
+}
+
+// substitute replaces the parameters in a line of a body with their values. Outside strings, any name that is a
+// parameter is replaced; inside strings, only a parameter joined to an ampersand is. An ampersand joined to a
+// replaced parameter is removed, so that X&N with N replaced by 1 becomes X1. Comments are left alone.
+func substitute(text string, values map[string]string) string {
+	if len(values) == 0 {
+		return text
+	}
+
+	var out strings.Builder
+	quoted := false
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ';' && !quoted:
+			out.WriteString(text[i:])
+			return out.String()
+		case c == '\'':
+			quoted = !quoted
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(text) && isNameChar(text[j]) {
+				j++
+			}
+			out.WriteString(text[i:j])
+			i = j
+			continue
+		case isNameStart(c):
+			name, _ := scanName(text[i:])
+			end := i + len(name)
+			before := out.Len() > 0 && strings.HasSuffix(out.String(), "&")
+			after := end < len(text) && text[end] == '&'
+
+			value, ok := values[strings.ToUpper(name)]
+			if !ok || (quoted && !before && !after) {
+				out.WriteString(name)
+				i = end
+				continue
+			}
+
+			if before {
+				trimmed := strings.TrimSuffix(out.String(), "&")
+				out.Reset()
+				out.WriteString(trimmed)
+			}
+			out.WriteString(value)
+			i = end
+			if after {
+				i++
+			}
+			continue
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String()
+}
+
+// splitArguments splits a macro argument list at commas. An argument enclosed in angle brackets may contain commas,
+// and is passed without the brackets; a quoted string is passed with its quotes.
+func splitArguments(field string) []string {
+	field = strings.TrimSpace(field)
+	if field == "" {
+		return nil
+	}
+
+	var args []string
+	var arg strings.Builder
+	quoted, brackets, parens := false, 0, 0
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+		case quoted:
+		case c == '<':
+			brackets++
+			if brackets == 1 {
+				continue
+			}
+		case c == '>' && brackets > 0:
+			brackets--
+			if brackets == 0 {
+				continue
+			}
+		case brackets > 0:
+		case c == '(':
+			parens++
+		case c == ')':
+			parens--
+		case c == ',' && parens == 0:
+			args = append(args, strings.TrimSpace(arg.String()))
+			arg.Reset()
+			continue
+		}
+		arg.WriteByte(c)
+	}
+	return append(args, strings.TrimSpace(arg.String()))
+}
+
+// include assembles the lines of another file. INCLUDE names the file; MACLIB names a library, read from the file
+// with the extension .LIB. The file is found relative to the directory of the including file.
+func (a *assembler) include(op string, field string) error {
+	name := strings.TrimSpace(field)
+	if strings.HasPrefix(name, "'") && strings.HasSuffix(name, "'") && len(name) > 1 {
+		name = name[1 : len(name)-1]
+	}
+	if name == "" {
+		return fmt.Errorf("%s requires a file name", op)
+	}
+	if op == "MACLIB" {
+		name += ".LIB"
+	}
+	if a.depth >= maxDepth {
+		return fmt.Errorf("files included more than %d deep", maxDepth)
+	}
+
+	path := filepath.Join(filepath.Dir(a.source.file), name)
+	source, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) && op == "MACLIB" {
+		path = filepath.Join(filepath.Dir(a.source.file), strings.ToLower(name))
+		source, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The included file is not part of any expansion that encloses the INCLUDE, so an EXITM in it cannot end one
+	including, expanding := a.source, a.expanding
+	a.depth++
+	a.expanding = 0
+	a.run(readLines(path, source))
+	a.expanding = expanding
+	a.depth--
+	a.source, a.exiting = including, false
+	return nil
+}
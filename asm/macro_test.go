@@ -0,0 +1,288 @@
+package asm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssemble_Conditionals(t *testing.T) {
+	source := `
+TRUE	EQU	0FFFFH
+FALSE	EQU	0
+	IF	TRUE
+	DB	1
+	IF	FALSE
+	DB	2
+	ELSE
+	DB	3
+	ENDIF
+	ELSE
+	DB	4
+	IF	TRUE
+	DB	5
+	ENDIF
+	ENDIF
+	IF	2 GT 1
+	DB	6
+	ENDIF
+`
+	program := assemble(t, source)
+
+	expected := []byte{1, 3, 6}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_Macro(t *testing.T) {
+	source := `
+SAVE	MACRO	R1,R2
+	PUSH	R1
+	PUSH	R2
+	ENDM
+STORE	MACRO	ADDR,VALUE
+	MVI	A,VALUE
+	STA	ADDR
+	ENDM
+START:	SAVE	B,<D>
+	STORE	1234H,'X'
+	SAVE	H,PSW
+`
+	program := assemble(t, source)
+
+	expected := []byte{0xC5, 0xD5, 0x3E, 'X', 0x32, 0x34, 0x12, 0xE5, 0xF5}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_MacroLocalLabels(t *testing.T) {
+	source := `
+WAIT	MACRO	N
+	LOCAL	LOOP
+	MVI	B,N
+LOOP:	DCR	B
+	JNZ	LOOP
+	ENDM
+	WAIT	2
+	WAIT	3
+`
+	program := assemble(t, source)
+
+	expected := []byte{0x06, 2, 0x05, 0xC2, 0x02, 0x00, 0x06, 3, 0x05, 0xC2, 0x08, 0x00}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_MacroConcatenation(t *testing.T) {
+	source := `
+NAMED	MACRO	N
+LABEL&N:	DB	'ITEM &N',N
+	ENDM
+	NAMED	1
+	NAMED	2
+	DW	LABEL2
+`
+	program := assemble(t, source)
+
+	expected := []byte("ITEM 1\x01ITEM 2\x02\x07\x00")
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+	if program.Symbols["LABEL1"] != 0 || program.Symbols["LABEL2"] != 7 {
+		t.Errorf("Expected LABEL1 at 0x0000 and LABEL2 at 0x0007 but got %v", program.Symbols)
+	}
+}
+
+func TestAssemble_MacroConditionals(t *testing.T) {
+	source := `
+LOAD	MACRO	R,V
+	IF	V EQ 0
+	MVI	R,0
+	EXITM
+	ENDIF
+	MVI	R,V
+	INR	R
+	ENDM
+	LOAD	A,0
+	LOAD	C,5
+`
+	program := assemble(t, source)
+
+	expected := []byte{0x3E, 0x00, 0x0E, 0x05, 0x0C}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_NestedMacros(t *testing.T) {
+	source := `
+INNER	MACRO	X
+	DB	X
+	ENDM
+OUTER	MACRO	X
+	INNER	X
+	INNER	X+1
+	ENDM
+	OUTER	10
+`
+	program := assemble(t, source)
+
+	expected := []byte{10, 11}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_Repeat(t *testing.T) {
+	source := `
+N	SET	0
+	REPT	3
+N	SET	N+1
+	DB	N
+	ENDM
+	IRP	R,<B,C,D>
+	INR	R
+	ENDM
+	IRPC	C,AZ
+	DB	'&C'
+	ENDM
+	REPT	5
+	DB	0FFH
+	IF	$ GE 0AH
+	EXITM
+	ENDIF
+	ENDM
+`
+	program := assemble(t, source)
+
+	expected := []byte{1, 2, 3, 0x04, 0x0C, 0x14, 'A', 'Z', 0xFF, 0xFF}
+	if !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_Include(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"defs.asm":   "SIZE\tEQU\t4\n",
+		"MACROS.LIB": "FILL\tMACRO\tV\n\tREPT\tSIZE\n\tDB\tV\n\tENDM\n\tENDM\n",
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(dir, "main.asm")
+	program, err := Assemble(path, []byte("\tINCLUDE\tdefs.asm\n\tMACLIB\tMACROS\n\tFILL\t7\n\tBAD\n"))
+	if err == nil {
+		t.Fatalf("Expected an error at line 4 of main.asm")
+	}
+	if !strings.Contains(err.Error(), "main.asm:4: unknown instruction BAD") {
+		t.Errorf("Expected the error to be reported at main.asm:4 but got %v", err)
+	}
+
+	program, err = Assemble(path, []byte("\tINCLUDE\tdefs.asm\n\tMACLIB\tMACROS\n\tFILL\t7\n"))
+	if err != nil {
+		t.Fatalf("Expected the program to assemble but got %v", err)
+	}
+	if expected := []byte{7, 7, 7, 7}; !bytes.Equal(program.Code, expected) {
+		t.Errorf("Expected % X but got % X", expected, program.Code)
+	}
+}
+
+func TestAssemble_IncludeExitm(t *testing.T) {
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "inc.asm"), []byte("\tDB\t1\n\tEXITM\n\tDB\t2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "main.asm")
+	for _, source := range []string{
+		"\tINCLUDE\tinc.asm\n\tDB\t3\n",
+		"MAC\tMACRO\n\tINCLUDE\tinc.asm\n\tENDM\n\tMAC\n\tDB\t3\n",
+	} {
+		_, err := Assemble(path, []byte(source))
+		if err == nil || !strings.Contains(err.Error(), "inc.asm:2: EXITM outside a macro") {
+			t.Errorf("Expected %q to fail with an EXITM outside a macro in inc.asm but got %v", source, err)
+		}
+	}
+}
+
+func TestAssemble_MacroErrors(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"\tIF\t1\n\tNOP\n", "test.asm:1: IF without ENDIF"},
+		{"\tELSE\n", "ELSE without IF"},
+		{"\tENDIF\n", "ENDIF without IF"},
+		{"\tIF\t1\n\tELSE\n\tELSE\n\tENDIF\n", "ELSE repeated"},
+		{"\tIF\tLATER\n\tENDIF\nLATER:\n", "IF operand must be defined before it is used"},
+		{"MAC\tMACRO\n\tNOP\n", "test.asm:1: MACRO without ENDM"},
+		{"\tENDM\n", "ENDM without MACRO, REPT or IRP"},
+		{"\tEXITM\n", "EXITM outside a macro"},
+		{"\tLOCAL\tX\n", "LOCAL outside a macro"},
+		{"\tMACRO\n\tENDM\n", "MACRO requires a name"},
+		{"MOV:\tMACRO\n\tENDM\n", "MOV is reserved"},
+		{"M\tMACRO\n\tENDM\n", "M is reserved"},
+		{"MAC\tMACRO\tX\n\tENDM\n\tMAC\t1,2\n", "MAC takes 1 arguments but has 2"},
+		{"MAC\tMACRO\n\tMAC\n\tENDM\n\tMAC\n", "macros nested more than 64 deep"},
+		{"MAC\tMACRO\n\tBAD\n\tENDM\n\tNOP\n\tMAC\n", "test.asm:5: unknown instruction BAD"},
+		{"\tIRP\tX\n\tENDM\n", "IRP takes a parameter and a list"},
+		{"\tINCLUDE\tmissing.asm\n", "missing.asm"},
+	}
+
+	for _, test := range tests {
+		_, err := Assemble("test.asm", []byte(test.source))
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("Expected %q to fail with %q but got %v", test.source, test.expected, err)
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	values := map[string]string{"X": "B", "N": "3"}
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"\tMOV\tA,X", "\tMOV\tA,B"},
+		{"\tMVI\tA,N+1", "\tMVI\tA,3+1"},
+		{"L&N:\tDB\t'X N &N'", "L3:\tDB\t'X N 3'"},
+		{"\tDB\tN&0H", "\tDB\t30H"},
+		{"\tDB\t0AH,NX", "\tDB\t0AH,NX"},
+		{"\tINR\tx\t; X stays in the comment", "\tINR\tB\t; X stays in the comment"},
+	}
+
+	for _, test := range tests {
+		if s := substitute(test.text, values); s != test.expected {
+			t.Errorf("Expected %q to become %q but got %q", test.text, test.expected, s)
+		}
+	}
+}
+
+func TestSplitArguments(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected []string
+	}{
+		{"", nil},
+		{"A, B ,C", []string{"A", "B", "C"}},
+		{"<1,2>,3", []string{"1,2", "3"}},
+		{"'a,b',(1,2)", []string{"'a,b'", "(1,2)"}},
+		{"<<X>>,", []string{"<X>", ""}},
+	}
+
+	for _, test := range tests {
+		args := splitArguments(test.field)
+		if strings.Join(args, "|") != strings.Join(test.expected, "|") || len(args) != len(test.expected) {
+			t.Errorf("Expected %q to split into %q but got %q", test.field, test.expected, args)
+		}
+	}
+}
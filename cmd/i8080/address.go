@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// addressFlag is a flag.Value holding a 16-bit address. Addresses may be written in decimal, or in hex with a 0x
+// prefix or Intel-style H suffix.
+type addressFlag struct {
+	value uint16
+	set   bool
+}
+
+func (a *addressFlag) String() string {
+	return fmt.Sprintf("0x%04X", a.value)
+}
+
+func (a *addressFlag) Set(s string) error {
+	v, err := parseAddress(s)
+	if err != nil {
+		return err
+	}
+	a.value, a.set = v, true
+	return nil
+}
+
+// parseAddress parses a 16-bit address written in decimal, 0x-prefixed hex or H-suffixed hex.
+func parseAddress(s string) (uint16, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'h' || s[n-1] == 'H') {
+		v, err := strconv.ParseUint(s[:n-1], 16, 16)
+		if err != nil {
+			return 0, fmt.Errorf("invalid address %q", s)
+		}
+		return uint16(v), nil
+	}
+
+	v, err := strconv.ParseUint(s, 0, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid address %q", s)
+	}
+	return uint16(v), nil
+}
+
+// addressListFlag is a flag.Value collecting the addresses given by repeating a flag.
+type addressListFlag []uint16
+
+func (a *addressListFlag) String() string {
+	s := make([]string, len(*a))
+	for i, v := range *a {
+		s[i] = fmt.Sprintf("0x%04X", v)
+	}
+	return strings.Join(s, ",")
+}
+
+func (a *addressListFlag) Set(s string) error {
+	v, err := parseAddress(s)
+	if err != nil {
+		return err
+	}
+	*a = append(*a, v)
+	return nil
+}
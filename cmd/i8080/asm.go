@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/cbush06/intel8080emulator/asm"
+)
+
+// asmCommand implements "i8080 asm", which assembles a source file into a raw binary holding the bytes from the
+// lowest address assembled to through the highest, and writes a listing (.lst) and symbol table (.sym) beside it.
+func asmCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("asm", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: i8080 asm [flags] program.asm")
+		flags.PrintDefaults()
+	}
+	output := flags.String("o", "", "binary to write (default the source file with the extension .bin)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	source, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "i8080 asm: %v\n", err)
+		return exitError
+	}
+
+	program, err := asm.Assemble(flags.Arg(0), source)
+	if err != nil {
+		var list asm.ErrorList
+		if errors.As(err, &list) {
+			for _, e := range list {
+				fmt.Fprintln(stderr, e)
+			}
+		} else {
+			fmt.Fprintf(stderr, "i8080 asm: %v\n", err)
+		}
+		return exitError
+	}
+
+	bin := *output
+	if bin == "" {
+		bin = strings.TrimSuffix(flags.Arg(0), filepath.Ext(flags.Arg(0))) + ".bin"
+	}
+	base := strings.TrimSuffix(bin, filepath.Ext(bin))
+
+	var listing, symbols bytes.Buffer
+	program.WriteListing(&listing)
+	program.WriteSymbols(&symbols)
+
+	for _, file := range []struct {
+		path string
+		data []byte
+	}{
+		{bin, program.Code},
+		{base + ".lst", listing.Bytes()},
+		{base + ".sym", symbols.Bytes()},
+	} {
+		if err := ioutil.WriteFile(file.path, file.data, 0644); err != nil {
+			fmt.Fprintf(stderr, "i8080 asm: %v\n", err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(stdout, "%s: %d bytes at 0x%04X\n", bin, len(program.Code), program.Origin)
+	return exitOK
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAsmCommand(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "program.asm")
+	if err := ioutil.WriteFile(path, []byte("\tORG\t100H\nSTART:\tJMP\tSTART\n\tEND\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := asmCommand([]string{path}, nil, &stdout, &stderr); status != exitOK {
+		t.Fatalf("Expected exit status %d but got %d: %s", exitOK, status, stderr.String())
+	}
+
+	code, err := ioutil.ReadFile(filepath.Join(dir, "program.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(code, []byte{0xC3, 0x00, 0x01}) {
+		t.Errorf("Expected program.bin to hold C3 00 01 but got % X", code)
+	}
+
+	listing, err := ioutil.ReadFile(filepath.Join(dir, "program.lst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(listing), "0100   C30001") {
+		t.Errorf("Expected the listing to show the JMP but got\n%s", listing)
+	}
+
+	symbols, err := ioutil.ReadFile(filepath.Join(dir, "program.sym"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(symbols) != "0100 START\n" {
+		t.Errorf("Expected the symbol table to hold START but got %q", symbols)
+	}
+}
+
+func TestAsmCommand_Output(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "program.asm")
+	if err := ioutil.WriteFile(path, []byte("\tNOP\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "out.com")
+	if status := asmCommand([]string{"-o", output, path}, nil, ioutil.Discard, ioutil.Discard); status != exitOK {
+		t.Fatalf("Expected exit status %d but got %d", exitOK, status)
+	}
+	for _, name := range []string{"out.com", "out.lst", "out.sym"} {
+		if _, err := ioutil.ReadFile(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be written but got %v", name, err)
+		}
+	}
+}
+
+func TestAsmCommand_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "program.asm")
+	if err := ioutil.WriteFile(path, []byte("\tNOP\n\tBAD\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	if status := asmCommand([]string{path}, nil, ioutil.Discard, &stderr); status != exitError {
+		t.Errorf("Expected exit status %d but got %d", exitError, status)
+	}
+	if !strings.Contains(stderr.String(), "program.asm:2: unknown instruction BAD") {
+		t.Errorf("Expected the error and its line but got %q", stderr.String())
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/cbush06/intel8080emulator/disasm"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// disasmCommand implements "i8080 disasm", which lists a raw binary as Intel assembly with the address and bytes of
+// every instruction. With -r it instead follows the flow of control from the entry points and writes source that
+// reassembles to the binary.
+func disasmCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("disasm", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: i8080 disasm [flags] program.bin")
+		flags.PrintDefaults()
+	}
+
+	var origin addressFlag
+	var entries addressListFlag
+	flags.Var(&origin, "org", "address the program is loaded at")
+	recursive := flags.Bool("r", false, "follow the flow of control and write reassemblable source")
+	flags.Var(&entries, "entry", "entry point for -r; may be repeated (default the reset and restart vectors and the load address)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	program, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "i8080 disasm: %v\n", err)
+		return exitError
+	}
+	if int(origin.value)+len(program) > memory.AddressSpaceSize {
+		fmt.Fprintf(stderr, "i8080 disasm: program of %d bytes loaded at 0x%04X does not fit in memory\n",
+			len(program), origin.value)
+		return exitError
+	}
+
+	if *recursive {
+		if len(entries) == 0 {
+			entries = append(disasm.DefaultEntries(), origin.value)
+		}
+
+		d, err := disasm.Disassemble(program, origin.value, entries...)
+		if err == nil {
+			err = d.WriteSource(stdout)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "i8080 disasm: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	list(out, program, origin.value)
+	return exitOK
+}
+
+// list writes a disassembly listing of program, loaded at origin, to w. An instruction that would extend past the
+// end of the program is listed as data.
+func list(w io.Writer, program []byte, origin uint16) {
+	ram := memory.NewRAM()
+	copy(ram[origin:], program)
+
+	end := int(origin) + len(program)
+	for addr := int(origin); addr < end; {
+		inst := disasm.Decode(ram, uint16(addr))
+		if addr+inst.Length > end {
+			for ; addr < end; addr++ {
+				fmt.Fprintf(w, "%04X  %02X        DB %s\n", addr, ram[addr], disasm.Hex(uint16(ram[addr]), 2))
+			}
+			break
+		}
+
+		fmt.Fprintln(w, inst.Listing())
+		addr += inst.Length
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+func TestDisasmCommand(t *testing.T) {
+	path := writeProgram(t, []byte{uint8(cpu.MVIA), 0x2A, uint8(cpu.JMP), 0x00, 0x01, uint8(cpu.LXIH), 0x00})
+
+	var stdout, stderr bytes.Buffer
+	if status := disasmCommand([]string{"-org", "100h", path}, nil, &stdout, &stderr); status != exitOK {
+		t.Fatalf("Expected exit status %d but got %d: %s", exitOK, status, stderr.String())
+	}
+
+	expected := "0100  3E 2A     MVI A,2AH\n" +
+		"0102  C3 00 01  JMP 0100H\n" +
+		"0105  21        DB 21H\n" +
+		"0106  00        DB 00H\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected listing\n%s\nbut got\n%s", expected, stdout.String())
+	}
+}
+
+func TestDisasmCommand_Usage(t *testing.T) {
+	var stderr bytes.Buffer
+	if status := disasmCommand(nil, nil, ioutil.Discard, &stderr); status != exitUsage {
+		t.Errorf("Expected exit status %d but got %d", exitUsage, status)
+	}
+}
+
+func TestDisasmCommand_Recursive(t *testing.T) {
+	path := writeProgram(t, []byte{uint8(cpu.JMP), 0x04, 0x01, 0xAA, uint8(cpu.HLT)})
+
+	var stdout, stderr bytes.Buffer
+	if status := disasmCommand([]string{"-r", "-org", "100h", "-entry", "100h", path}, nil, &stdout, &stderr); status != exitOK {
+		t.Fatalf("Expected exit status %d but got %d: %s", exitOK, status, stderr.String())
+	}
+
+	expected := "\tORG\t0100H\n\tJMP\tL0104\n\tDB\t0AAH\nL0104:\tHLT\n\tEND\n"
+	if stdout.String() != expected {
+		t.Errorf("Expected source\n%s\nbut got\n%s", expected, stdout.String())
+	}
+}
@@ -0,0 +1,62 @@
+// Command i8080 runs Intel 8080 programs on the emulator.
+//
+// Usage:
+//
+//	i8080 <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	run     load a raw binary into memory and execute it
+//	disasm  list a raw binary as Intel assembly
+//	asm     assemble Intel 8080 source into a raw binary
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is an i8080 subcommand. run parses args and returns the process exit status.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"run", "load a raw binary into memory and execute it", runCommand},
+	{"disasm", "list a raw binary as Intel assembly", disasmCommand},
+	{"asm", "assemble Intel 8080 source into a raw binary", asmCommand},
+}
+
+func main() {
+	os.Exit(dispatch(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// dispatch runs the subcommand named by args[0] and returns its exit status.
+func dispatch(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:], stdin, stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "i8080: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: i8080 <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+func TestParseAddress(t *testing.T) {
+	var tests = []struct {
+		in       string
+		expected uint16
+	}{
+		{"256", 0x100},
+		{"0x100", 0x100},
+		{"100h", 0x100},
+		{"0FFFFH", 0xFFFF},
+	}
+
+	for _, test := range tests {
+		if v, err := parseAddress(test.in); err != nil || v != test.expected {
+			t.Errorf("Expected %q to parse as 0x%X but got 0x%X, %v", test.in, test.expected, v, err)
+		}
+	}
+
+	for _, in := range []string{"", "h", "10000h", "0x10000", "xyz"} {
+		if _, err := parseAddress(in); err == nil {
+			t.Errorf("Expected %q to be rejected but it was accepted", in)
+		}
+	}
+}
+
+func TestDispatch_UnknownCommand(t *testing.T) {
+	var stderr bytes.Buffer
+	if status := dispatch([]string{"bogus"}, nil, ioutil.Discard, &stderr); status != 2 {
+		t.Errorf("Expected exit status 2 but got %d", status)
+	}
+
+	if !strings.Contains(stderr.String(), "usage: i8080") {
+		t.Errorf("Expected usage to be printed but got %q", stderr.String())
+	}
+}
+
+func TestDispatch_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "halt.bin")
+	if err := ioutil.WriteFile(path, []byte{uint8(cpu.DI), uint8(cpu.HLT)}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stderr bytes.Buffer
+	if status := dispatch([]string{"run", "-org", "0x100", path}, nil, ioutil.Discard, &stderr); status != 0 {
+		t.Errorf("Expected exit status 0 but got %d: %s", status, stderr.String())
+	}
+}
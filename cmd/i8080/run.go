@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/emulator"
+)
+
+// Exit statuses reported by the i8080 commands. Only "i8080 run" reports exitHalted, exitLimit, exitTimeout and
+// exitInterrupted.
+const (
+	exitOK          = 0   // The command succeeded
+	exitHalted      = 0   // The program executed HLT
+	exitError       = 1   // The program could not be loaded, or the CPU faulted
+	exitUsage       = 2   // The command line was invalid
+	exitLimit       = 3   // The cycle or instruction limit was reached
+	exitTimeout     = 4   // The timeout expired
+	exitInterrupted = 130 // The user interrupted the program
+)
+
+// runCommand implements "i8080 run", which loads a raw binary, connects a console device to stdin and stdout, and
+// executes the program until it halts, faults, reaches a limit, times out or the user interrupts it.
+func runCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: i8080 run [flags] program.bin")
+		flags.PrintDefaults()
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "exit status: 0 halted, 1 error or fault, 2 usage, 3 limit reached, 4 timeout, 130 interrupted")
+	}
+
+	var origin, entry, stack addressFlag
+	flags.Var(&origin, "org", "address the program is loaded at")
+	flags.Var(&entry, "entry", "address execution begins at (default the load address)")
+	flags.Var(&stack, "sp", "initial stack pointer")
+	memorySize := flags.Int("mem", 0x10000, "bytes of RAM installed from address 0")
+	speed := flags.Int("speed", emulator.DefaultClockSpeed, "clock rate in Hz, or 0 to run as fast as possible")
+	statusPort := flags.Uint("console-status", 0, "console status port")
+	dataPort := flags.Uint("console-data", 1, "console data port")
+	cycleLimit := flags.Uint64("cycles", 0, "stop after this many T-states (0 for no limit)")
+	instructionLimit := flags.Uint64("instructions", 0, "stop after this many instructions (0 for no limit)")
+	timeout := flags.Duration("timeout", 0, "stop after this much host time (0 for no limit)")
+	strict := flags.Bool("strict", false, "trap on undocumented opcodes")
+	powerOn := flags.String("power-on", "zero", "initial register and memory contents: zero, pattern or random")
+	fill := flags.Uint("fill", 0xFF, "fill byte for -power-on pattern")
+	seed := flags.Int64("seed", 1, "random seed for -power-on random")
+	verbose := flags.Bool("v", false, "report how the program ended and the cycles and instructions executed")
+
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return exitUsage
+	}
+	if *statusPort > 0xFF || *dataPort > 0xFF || *statusPort == *dataPort {
+		fmt.Fprintln(stderr, "i8080 run: console ports must be distinct values from 0 to 255")
+		return exitUsage
+	}
+
+	state, err := powerOnState(*powerOn, *fill, *seed)
+	if err != nil {
+		fmt.Fprintf(stderr, "i8080 run: %v\n", err)
+		return exitUsage
+	}
+
+	program, err := ioutil.ReadFile(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "i8080 run: %v\n", err)
+		return exitError
+	}
+
+	console := emulator.NewConsole(stdin, stdout, uint8(*statusPort), uint8(*dataPort))
+	options := []emulator.Option{
+		emulator.WithLoadAddress(origin.value),
+		emulator.WithPowerOnState(state),
+		emulator.WithMemorySize(*memorySize),
+		emulator.WithClockSpeed(*speed),
+		emulator.WithDevice(console.StatusPort, console),
+		emulator.WithDevice(console.DataPort, console),
+		emulator.WithStopOnHalt(),
+		emulator.WithCycleLimit(*cycleLimit),
+		emulator.WithInstructionLimit(*instructionLimit),
+	}
+	if entry.set {
+		options = append(options, emulator.WithEntryPoint(entry.value))
+	}
+	if stack.set {
+		options = append(options, emulator.WithStackPointer(stack.value))
+	}
+
+	m, err := emulator.New(program, options...)
+	if err != nil {
+		fmt.Fprintf(stderr, "i8080 run: %v\n", err)
+		return exitError
+	}
+	m.Inspect(func(c *cpu.CPU) { c.StrictOpcodes = *strict })
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
+	status, reason := runStatus(m.Run(ctx))
+	if *verbose || status != exitHalted {
+		m.Inspect(func(c *cpu.CPU) {
+			fmt.Fprintf(stderr, "i8080 run: %s at 0x%04X after %d instructions, %d cycles\n",
+				reason, c.ProgramCounter, c.Instructions, c.Cycles)
+		})
+	}
+	return status
+}
+
+// powerOnState parses the -power-on, -fill and -seed flags.
+func powerOnState(policy string, fill uint, seed int64) (cpu.PowerOnState, error) {
+	if fill > 0xFF {
+		return cpu.PowerOnState{}, fmt.Errorf("fill byte %d is not a value from 0 to 255", fill)
+	}
+
+	switch policy {
+	case "zero":
+		return cpu.PowerOnState{Policy: cpu.PowerOnZeroed}, nil
+	case "pattern":
+		return cpu.PowerOnState{Policy: cpu.PowerOnPattern, Pattern: uint8(fill)}, nil
+	case "random":
+		return cpu.PowerOnState{Policy: cpu.PowerOnRandom, Seed: seed}, nil
+	default:
+		return cpu.PowerOnState{}, fmt.Errorf("unknown power-on state %q", policy)
+	}
+}
+
+// runStatus maps the error returned by Machine.Run to an exit status and a description of how the program ended.
+func runStatus(err error) (int, string) {
+	switch {
+	case err == nil:
+		return exitHalted, "halted"
+	case errors.Is(err, emulator.ErrCycleLimit), errors.Is(err, emulator.ErrInstructionLimit):
+		return exitLimit, err.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return exitTimeout, "timed out"
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted, "interrupted"
+	default:
+		return exitError, err.Error()
+	}
+}
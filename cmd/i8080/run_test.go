@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+// echoProgram copies console input to console output until end-of-file, then halts. It is loaded at 0x100.
+var echoProgram = []byte{
+	uint8(cpu.IN), 0x00, // 0x100: poll the status port
+	uint8(cpu.ORAA),
+	uint8(cpu.JZ), 0x00, 0x01,
+	uint8(cpu.IN), 0x01, // 0x106: read the data port
+	uint8(cpu.CPI), 0x1A,
+	uint8(cpu.JZ), 0x12, 0x01,
+	uint8(cpu.OUT), 0x01, // 0x10D: echo the byte
+	uint8(cpu.JMP), 0x00, 0x01,
+	uint8(cpu.HLT), // 0x112
+}
+
+func writeProgram(t *testing.T, program []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "program.bin")
+	if err := ioutil.WriteFile(path, program, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunCommand_Console(t *testing.T) {
+	path := writeProgram(t, echoProgram)
+
+	var stdout, stderr bytes.Buffer
+	status := runCommand([]string{"-org", "100h", "-speed", "0", path}, strings.NewReader("HELLO"), &stdout, &stderr)
+	if status != exitHalted {
+		t.Fatalf("Expected exit status %d but got %d: %s", exitHalted, status, stderr.String())
+	}
+
+	if stdout.String() != "HELLO" {
+		t.Errorf("Expected the program to echo \"HELLO\" but wrote %q", stdout.String())
+	}
+}
+
+func TestRunCommand_Endings(t *testing.T) {
+	loop := []byte{uint8(cpu.JMP), 0x00, 0x01}
+
+	var tests = []struct {
+		name     string
+		program  []byte
+		args     []string
+		expected int
+	}{
+		{"halt with interrupts enabled", []byte{uint8(cpu.EI), uint8(cpu.HLT)}, nil, exitHalted},
+		{"cycle limit", loop, []string{"-cycles", "1000"}, exitLimit},
+		{"instruction limit", loop, []string{"-instructions", "10"}, exitLimit},
+		{"timeout", loop, []string{"-timeout", "10ms"}, exitTimeout},
+		{"fault", []byte{uint8(cpu.NOP08)}, []string{"-strict"}, exitError},
+		{"usage", nil, []string{"-console-data", "0"}, exitUsage},
+		{"power-on state", nil, []string{"-power-on", "garbage"}, exitUsage},
+		{"fill byte", nil, []string{"-power-on", "pattern", "-fill", "256"}, exitUsage},
+	}
+
+	for _, test := range tests {
+		args := append([]string{"-org", "0x100", "-speed", "0"}, test.args...)
+		args = append(args, writeProgram(t, test.program))
+
+		var stderr bytes.Buffer
+		if status := runCommand(args, nil, ioutil.Discard, &stderr); status != test.expected {
+			t.Errorf("%s: expected exit status %d but got %d: %s", test.name, test.expected, status, stderr.String())
+		}
+	}
+}
+
+func TestRunCommand_MissingFile(t *testing.T) {
+	var stderr bytes.Buffer
+	if status := runCommand([]string{filepath.Join(t.TempDir(), "missing.bin")}, nil, ioutil.Discard, &stderr); status != exitError {
+		t.Errorf("Expected exit status %d but got %d", exitError, status)
+	}
+}
@@ -0,0 +1,169 @@
+// Package conformance runs the standard Intel 8080 exerciser suites (8080PRE, 8080EXM, TST8080 and SuperSoft
+// CPUTEST) under a minimal CP/M BDOS shim and reports the result of each instruction group they test.
+package conformance
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+const (
+	// warmBoot is the CP/M warm boot entry point a program jumps to when it exits.
+	warmBoot uint16 = 0x0000
+
+	// bdosEntry is the CP/M BDOS entry point a program calls for console I/O.
+	bdosEntry uint16 = 0x0005
+
+	// bdosBase is the address reported at 0x0006 as the base of the BDOS. Programs use it as the top of their
+	// memory and set their stack below it.
+	bdosBase uint16 = 0xFE00
+
+	// tpa is the start of the CP/M transient program area, where .COM programs are loaded and begin.
+	tpa uint16 = 0x0100
+)
+
+// BDOS functions emulated by the shim.
+const (
+	bdosSystemReset  = 0
+	bdosConsoleWrite = 2
+	bdosPrintString  = 9
+)
+
+// DefaultInstructionLimit bounds a run so that a broken CPU cannot loop forever. It comfortably exceeds the
+// number of instructions the longest exerciser (8080EXM) executes.
+const DefaultInstructionLimit = 50000000000
+
+// GroupResult is the outcome of one instruction group reported by an exerciser.
+type GroupResult struct {
+	Name     string
+	Passed   bool
+	Expected string // CRC the exerciser expected, if it reports one
+	Found    string // CRC the exerciser computed, if it reports one
+}
+
+func (g GroupResult) String() string {
+	switch {
+	case g.Passed:
+		return fmt.Sprintf("%s: PASS", g.Name)
+	case g.Expected != "" || g.Found != "":
+		return fmt.Sprintf("%s: FAIL (crc expected %s, found %s)", g.Name, g.Expected, g.Found)
+	default:
+		return fmt.Sprintf("%s: FAIL", g.Name)
+	}
+}
+
+// Result is the outcome of running an exerciser.
+type Result struct {
+	Output       string        // Everything the exerciser wrote to the console
+	Groups       []GroupResult // Per-group results parsed from Output
+	Complete     bool          // Whether the exerciser reported that it ran to completion
+	Instructions uint64
+	Cycles       uint64
+}
+
+// Passed reports whether the exerciser ran to completion and every group passed.
+func (r *Result) Passed() bool {
+	return r.Complete && len(r.Failed()) == 0
+}
+
+// Failed returns the groups that did not pass.
+func (r *Result) Failed() []GroupResult {
+	var failed []GroupResult
+	for _, group := range r.Groups {
+		if !group.Passed {
+			failed = append(failed, group)
+		}
+	}
+	return failed
+}
+
+// Run loads a CP/M .COM program into the transient program area, runs it until it exits to CP/M and parses its
+// console output with suite. It returns an error if the CPU faults, halts or executes more than limit
+// instructions; the Result holds whatever the program had written by then.
+func Run(suite Suite, program []byte, limit uint64) (*Result, error) {
+	if int(tpa)+len(program) > int(bdosBase) {
+		return nil, fmt.Errorf("%s: program of %d bytes does not fit in the transient program area", suite.Name, len(program))
+	}
+
+	c := new(cpu.CPU)
+	c.Init()
+	c.ProgramCounter = tpa
+
+	// JMP to the BDOS base, so that programs reading 0x0006 find the top of their memory
+	c.Memory.Write(bdosEntry, uint8(cpu.JMP))
+	c.Memory.Write(bdosEntry+1, uint8(bdosBase&0xFF))
+	c.Memory.Write(bdosEntry+2, uint8(bdosBase>>8))
+
+	for i, b := range program {
+		c.Memory.Write(tpa+uint16(i), b)
+	}
+
+	var console strings.Builder
+	err := run(c, &console, limit)
+
+	result := &Result{
+		Output:       console.String(),
+		Instructions: c.Instructions,
+		Cycles:       c.Cycles,
+	}
+	result.Groups, result.Complete = suite.Parse(result.Output)
+
+	if err != nil {
+		return result, fmt.Errorf("%s: %v", suite.Name, err)
+	}
+	return result, nil
+}
+
+// run executes instructions until the program exits to CP/M, servicing BDOS calls along the way.
+func run(c *cpu.CPU, console *strings.Builder, limit uint64) error {
+	for c.ProgramCounter != warmBoot {
+		if c.Instructions >= limit {
+			return fmt.Errorf("program did not exit after %d instructions", limit)
+		}
+
+		if c.ProgramCounter == bdosEntry {
+			if exit := bdos(c, console); exit {
+				return nil
+			}
+			continue
+		}
+
+		c.StandardInstructionCycle()
+		if c.Fault != nil {
+			return c.Fault
+		}
+		if c.Halted {
+			return fmt.Errorf("CPU halted at 0x%04X", c.ProgramCounter)
+		}
+	}
+	return nil
+}
+
+// bdos emulates the BDOS call the program has just made and returns from it. It reports whether the program asked
+// to exit to CP/M.
+func bdos(c *cpu.CPU, console *strings.Builder) bool {
+	var function uint8
+	c.C.Read8(&function)
+
+	switch function {
+	case bdosSystemReset:
+		return true
+	case bdosConsoleWrite:
+		var e uint8
+		c.E.Read8(&e)
+		console.WriteByte(e)
+	case bdosPrintString:
+		var addr uint16
+		c.DE.Read16(&addr)
+		for n := 0; n < memory.AddressSpaceSize && c.Memory.Read(addr) != '$'; n++ {
+			console.WriteByte(c.Memory.Read(addr))
+			addr++
+		}
+	}
+
+	c.Return()
+	return false
+}
@@ -0,0 +1,66 @@
+package conformance
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+func TestRun_InstructionLimit(t *testing.T) {
+	program := []byte{uint8(cpu.JMP), 0x00, 0x01}
+
+	result, err := Run(Diagnostic, program, 100)
+	if err == nil {
+		t.Fatal("Expected a program that never exits to fail but it succeeded")
+	}
+
+	if result.Instructions != 100 || result.Passed() {
+		t.Errorf("Expected a failed result after 100 instructions but got %d instructions, passed=%v", result.Instructions, result.Passed())
+	}
+}
+
+func TestRun_Halted(t *testing.T) {
+	if _, err := Run(Diagnostic, []byte{uint8(cpu.HLT)}, 100); err == nil {
+		t.Error("Expected a program that halts to fail but it succeeded")
+	}
+}
+
+// TestSuites runs each exerciser found in testdata and reports every instruction group that fails. 8080EXM takes
+// several minutes and is skipped in short mode.
+func TestSuites(t *testing.T) {
+	for _, suite := range Suites {
+		suite := suite
+		t.Run(suite.Name, func(t *testing.T) {
+			program, err := ioutil.ReadFile(filepath.Join("testdata", suite.File))
+			if os.IsNotExist(err) {
+				t.Skipf("testdata/%s not present", suite.File)
+			} else if err != nil {
+				t.Fatal(err)
+			}
+
+			if suite.Name == Exerciser.Name && testing.Short() {
+				t.Skip("skipping 8080EXM in short mode")
+			}
+
+			result, err := Run(suite, program, DefaultInstructionLimit)
+			if err != nil {
+				t.Errorf("%v; output: %q", err, result.Output)
+			}
+
+			for _, group := range result.Groups {
+				if group.Passed {
+					t.Log(group)
+				} else {
+					t.Error(group)
+				}
+			}
+
+			if !result.Complete {
+				t.Errorf("Expected %s to run to completion; output: %q", suite.Name, result.Output)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package conformance
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Suite describes an exerciser: the .COM file it is distributed as and how to parse its console output into
+// per-group results.
+type Suite struct {
+	Name  string
+	File  string
+	Parse func(output string) (groups []GroupResult, complete bool)
+}
+
+var (
+	// exerciserGroup matches an 8080EXM result line such as
+	//	aluop nn......................  PASS! crc is:9e922f9e
+	//	dad <b,d,h,sp>................  ERROR **** crc expected:14474ba6 found:12345678
+	exerciserGroup = regexp.MustCompile(`^(.+?)\.{2,}\s*(PASS!|ERROR)(.*)$`)
+	exerciserCRC   = regexp.MustCompile(`crc is:([0-9a-fA-F]{8})`)
+	exerciserError = regexp.MustCompile(`expected:([0-9a-fA-F]{8})\s+found:([0-9a-fA-F]{8})`)
+
+	// diagnosticError matches the Microcosm diagnostic's failure report, which includes the address of the
+	// failing check.
+	diagnosticError = regexp.MustCompile(`CPU HAS FAILED!\s*ERROR EXIT=\s*([0-9A-Fa-f]{4})`)
+)
+
+// Standard exerciser suites. Place the .COM files in a testdata directory to run them.
+var (
+	// Preliminary tests that 8080EXM relies on. A failure is reported as a single group.
+	Preliminary = Suite{Name: "8080PRE", File: "8080PRE.COM", Parse: parsePreliminary}
+
+	// Frank Cringle's exhaustive exerciser, adapted for the 8080 by Ian Bartholomew. Each instruction group is
+	// reported with its CRC.
+	Exerciser = Suite{Name: "8080EXM", File: "8080EXM.COM", Parse: parseExerciser}
+
+	// The Microcosm Associates diagnostic, of which cpudiag.bin is a build.
+	Diagnostic = Suite{Name: "TST8080", File: "TST8080.COM", Parse: parseDiagnostic}
+
+	// SuperSoft Associates' CPU test.
+	SuperSoft = Suite{Name: "CPUTEST", File: "CPUTEST.COM", Parse: parseSuperSoft}
+)
+
+// Suites lists every standard exerciser suite, in the order they are normally run.
+var Suites = []Suite{Preliminary, Diagnostic, SuperSoft, Exerciser}
+
+// lines splits console output into lines, discarding carriage returns and form feeds.
+func lines(output string) []string {
+	output = strings.NewReplacer("\r", "", "\f", "").Replace(output)
+	return strings.Split(output, "\n")
+}
+
+func parseExerciser(output string) ([]GroupResult, bool) {
+	var groups []GroupResult
+	for _, line := range lines(output) {
+		match := exerciserGroup.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+
+		group := GroupResult{Name: strings.TrimSpace(match[1]), Passed: match[2] == "PASS!"}
+		if crc := exerciserCRC.FindStringSubmatch(match[3]); crc != nil {
+			group.Found = crc[1]
+		}
+		if crc := exerciserError.FindStringSubmatch(match[3]); crc != nil {
+			group.Expected, group.Found = crc[1], crc[2]
+		}
+		groups = append(groups, group)
+	}
+	return groups, strings.Contains(output, "Tests complete")
+}
+
+func parsePreliminary(output string) ([]GroupResult, bool) {
+	complete := strings.Contains(output, "Preliminary tests complete")
+	passed := complete && !strings.Contains(output, "ERROR") && !strings.Contains(output, "failed")
+	return []GroupResult{{Name: "preliminary", Passed: passed}}, complete
+}
+
+func parseDiagnostic(output string) ([]GroupResult, bool) {
+	if match := diagnosticError.FindStringSubmatch(output); match != nil {
+		return []GroupResult{{Name: "error exit " + strings.ToUpper(match[1])}}, true
+	}
+
+	passed := strings.Contains(output, "CPU IS OPERATIONAL")
+	return []GroupResult{{Name: "diagnostic", Passed: passed}}, passed
+}
+
+func parseSuperSoft(output string) ([]GroupResult, bool) {
+	var groups []GroupResult
+	for _, line := range lines(output) {
+		if strings.Contains(line, "ERROR") {
+			groups = append(groups, GroupResult{Name: strings.TrimSpace(line)})
+		}
+	}
+
+	complete := strings.Contains(output, "CPU TESTS OK")
+	if len(groups) == 0 {
+		groups = append(groups, GroupResult{Name: "cputest", Passed: complete})
+	}
+	return groups, complete
+}
@@ -0,0 +1,65 @@
+package conformance
+
+import "testing"
+
+func TestParseExerciser(t *testing.T) {
+	output := "8080 instruction exerciser\n\r" +
+		"dad <b,d,h,sp>................  PASS! crc is:14474ba6\n\r" +
+		"aluop nn......................  ERROR **** crc expected:9e922f9e found:12345678\n\r" +
+		"Tests complete$"
+
+	groups, complete := parseExerciser(output)
+	if !complete {
+		t.Error("Expected output to be complete but it was not")
+	}
+
+	expected := []GroupResult{
+		{Name: "dad <b,d,h,sp>", Passed: true, Found: "14474ba6"},
+		{Name: "aluop nn", Expected: "9e922f9e", Found: "12345678"},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups but parsed %v", len(expected), groups)
+	}
+
+	for i := range expected {
+		if groups[i] != expected[i] {
+			t.Errorf("Expected group %+v but parsed %+v", expected[i], groups[i])
+		}
+	}
+
+	result := &Result{Groups: groups, Complete: complete}
+	if failed := result.Failed(); len(failed) != 1 || failed[0].Name != "aluop nn" {
+		t.Errorf("Expected only aluop nn to fail but got %v", failed)
+	}
+}
+
+func TestParsePreliminary(t *testing.T) {
+	if groups, complete := parsePreliminary("8080 Preliminary tests complete"); !complete || !groups[0].Passed {
+		t.Errorf("Expected preliminary tests to pass but got %v, complete=%v", groups, complete)
+	}
+
+	if groups, _ := parsePreliminary("ERROR at 0x0123"); groups[0].Passed {
+		t.Error("Expected preliminary tests to fail but they passed")
+	}
+}
+
+func TestParseDiagnostic(t *testing.T) {
+	if groups, complete := parseDiagnostic("\f\r\n CPU IS OPERATIONAL"); !complete || !groups[0].Passed {
+		t.Errorf("Expected diagnostic to pass but got %v, complete=%v", groups, complete)
+	}
+
+	groups, _ := parseDiagnostic("\f\r\n CPU HAS FAILED!    ERROR EXIT=01c2")
+	if groups[0].Passed || groups[0].Name != "error exit 01C2" {
+		t.Errorf("Expected a failure at 01C2 but got %v", groups)
+	}
+}
+
+func TestParseSuperSoft(t *testing.T) {
+	if groups, complete := parseSuperSoft("CPU TESTS OK"); !complete || !groups[0].Passed {
+		t.Errorf("Expected CPUTEST to pass but got %v, complete=%v", groups, complete)
+	}
+
+	if groups, _ := parseSuperSoft("DAA ERROR\r\nCPU TESTS OK"); groups[0].Passed || groups[0].Name != "DAA ERROR" {
+		t.Errorf("Expected the DAA error to be reported but got %v", groups)
+	}
+}
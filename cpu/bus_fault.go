@@ -0,0 +1,30 @@
+package cpu
+
+import (
+	"fmt"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// BusFaultError is the fault raised when the memory bus refuses an access, such as a write to a ROM region with
+// the memory.WriteFault policy. The instruction at Address completes without the refused access, and the CPU stops
+// before the next one.
+type BusFaultError struct {
+	Address uint16
+	Err     error
+}
+
+func (e *BusFaultError) Error() string {
+	return fmt.Sprintf("%v by instruction at address 0x%04X", e.Err, e.Address)
+}
+
+func (e *BusFaultError) Unwrap() error {
+	return e.Err
+}
+
+// checkBusFault raises a BusFaultError if the memory bus refused an access made by the instruction at address.
+func (cpu *CPU) checkBusFault(address uint16) {
+	if err := memory.TakeFault(cpu.Memory); err != nil && cpu.Fault == nil {
+		cpu.Raise(&BusFaultError{Address: address, Err: err})
+	}
+}
@@ -0,0 +1,148 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+func TestCPU_BusFault(t *testing.T) {
+	rom := []uint8{uint8(MVIA), 0x2A, uint8(STA), 0x00, 0x00, uint8(NOP)}
+	bus, err := memory.NewMapBuilder().
+		ROM("rom", 0x0000, rom, memory.WriteFault).
+		RAM("ram", 0x2000, 0x400).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := &CPU{Memory: bus}
+	cpu.Init()
+
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	var busFault *BusFaultError
+	if !errors.As(cpu.Fault, &busFault) {
+		t.Fatalf("Expected a BusFaultError but got %v", cpu.Fault)
+	}
+	if busFault.Address != 0x0002 {
+		t.Errorf("Expected the fault to report the STA at 0x0002 but reported 0x%04X", busFault.Address)
+	}
+
+	var writeErr *memory.WriteError
+	if !errors.As(cpu.Fault, &writeErr) || writeErr.Address != 0x0000 {
+		t.Errorf("Expected the fault to wrap the write to 0x0000 but got %v", cpu.Fault)
+	}
+
+	if bus.Read(0x0000) != uint8(MVIA) {
+		t.Error("Expected the ROM to be unchanged")
+	}
+
+	if cycles := cpu.StandardInstructionCycle(); cycles != 0 {
+		t.Errorf("Expected the CPU to stop after the fault but it consumed %d cycles", cycles)
+	}
+}
+
+func TestCPU_BusFaultFromOutsideCPU(t *testing.T) {
+	bus, err := memory.NewMapBuilder().
+		ROM("rom", 0x0000, []uint8{uint8(NOP), uint8(HLT)}, memory.WriteFault).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := &CPU{Memory: bus}
+	cpu.Init()
+
+	// A write that did not come from an instruction must not be blamed on the next one
+	bus.Write(0x0001, uint8(INRA))
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	if cpu.Fault != nil {
+		t.Errorf("Expected no fault but got %v", cpu.Fault)
+	}
+	if !cpu.Halted {
+		t.Error("Expected the CPU to execute the HLT in ROM")
+	}
+}
+
+func TestCPU_PowerOnFillsOnlyRAM(t *testing.T) {
+	bus, err := memory.NewMapBuilder().
+		ROM("rom", 0x0000, []uint8{uint8(HLT)}, memory.WriteFault).
+		RAM("ram", 0x2000, 0x400).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := &CPU{Memory: bus}
+	cpu.Init()
+	cpu.PowerOn(PowerOnState{Policy: PowerOnPattern, Pattern: 0xE5})
+
+	if bus.Read(0x0000) != uint8(HLT) || bus.Read(0x2000) != 0xE5 {
+		t.Error("Expected power-on to fill the RAM and leave the ROM alone")
+	}
+
+	cpu.StandardInstructionCycle()
+	if cpu.Fault != nil || !cpu.Halted {
+		t.Errorf("Expected the ROM program to run without a fault but got %v", cpu.Fault)
+	}
+}
+
+// recordingDevice is a memory-mapped device that records every access.
+type recordingDevice struct {
+	reads  []uint16
+	writes map[uint16]uint8
+}
+
+func (d *recordingDevice) Read(offset uint16) uint8 {
+	d.reads = append(d.reads, offset)
+	return 0x40 + uint8(offset)
+}
+
+func (d *recordingDevice) Write(offset uint16, v uint8) {
+	d.writes[offset] = v
+}
+
+func TestCPU_MemoryMappedDevice(t *testing.T) {
+	device := &recordingDevice{writes: make(map[uint16]uint8)}
+	bus, err := memory.NewMapBuilder().
+		RAM("ram", 0x0000, 0x1000).
+		Device("device", 0xF000, 0x100, device).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cpu := &CPU{Memory: bus}
+	cpu.Init()
+
+	// MOV A,M reads offset 0x10; STA writes offset 0x20; PUSH H with SP = 0xF002 writes offsets 0x01 and 0x00
+	program := []uint8{
+		uint8(LXIH), 0x10, 0xF0,
+		uint8(MOVAM),
+		uint8(STA), 0x20, 0xF0,
+		uint8(LXISP), 0x02, 0xF0,
+		uint8(PUSHH),
+	}
+	for i, b := range program {
+		bus.Write(uint16(i), b)
+	}
+
+	for i := 0; i < 5; i++ {
+		cpu.StandardInstructionCycle()
+	}
+
+	var a uint8
+	cpu.A.Read8(&a)
+	if a != 0x50 || len(device.reads) != 1 || device.reads[0] != 0x10 {
+		t.Errorf("Expected MOV A,M to read offset 0x10 but A was 0x%X and reads were %v", a, device.reads)
+	}
+
+	if device.writes[0x20] != 0x50 || device.writes[0x01] != 0xF0 || device.writes[0x00] != 0x10 {
+		t.Errorf("Expected STA and PUSH to write to the device but got %v", device.writes)
+	}
+}
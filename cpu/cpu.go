@@ -6,36 +6,51 @@ import (
 )
 
 // CPU represents the collection of components that comprise the 8080's central processing unit. In short,
-// it encapsulates the ALU, registers, and interpreter.
+// it encapsulates the ALU, registers, and interpreter. The undocumented opcode aliases execute like their
+// documented counterparts unless StrictOpcodes is set, in which case they are treated as unimplemented. An
+// unimplemented opcode is passed to the UnimplementedOpcode hook if one is set, and otherwise stops the CPU with an
+// UnimplementedOpcodeError. Like the hardware, all address arithmetic is 16-bit and wraps modulo 64 KiB: the stack
+// may straddle 0xFFFF/0x0000 and an instruction at 0xFFFE or 0xFFFF fetches its operands from 0x0000 onwards.
 type CPU struct {
-	ProgramCounter     uint16
-	SP                 memory.RegisterPair
-	InterruptsEnabled  bool
-	Write              bool
-	DataBus            memory.Register
-	AddressBus         memory.RegisterPair
-	A                  memory.Register
-	BC                 memory.RegisterPair
-	B                  *memory.Register
-	C                  *memory.Register
-	DE                 memory.RegisterPair
-	D                  *memory.Register
-	E                  *memory.Register
-	HL                 memory.RegisterPair
-	H                  *memory.Register
-	L                  *memory.Register
-	WZ                 memory.RegisterPair
-	W                  *memory.Register
-	Z                  *memory.Register
-	ALU                alu.ALU
-	Memory             []uint8
-	RegisterLookup     [8]*memory.Register
-	RegisterPairLookup [4]*memory.RegisterPair
+	ProgramCounter      uint16
+	SP                  memory.RegisterPair
+	InterruptsEnabled   bool
+	Halted              bool
+	Cycles              uint64
+	Instructions        uint64
+	Write               bool
+	DataBus             memory.Register
+	AddressBus          memory.RegisterPair
+	A                   memory.Register
+	BC                  memory.RegisterPair
+	B                   *memory.Register
+	C                   *memory.Register
+	DE                  memory.RegisterPair
+	D                   *memory.Register
+	E                   *memory.Register
+	HL                  memory.RegisterPair
+	H                   *memory.Register
+	L                   *memory.Register
+	WZ                  memory.RegisterPair
+	W                   *memory.Register
+	Z                   *memory.Register
+	ALU                 alu.ALU
+	Memory              memory.Bus
+	Ports               [256]IODevice
+	UnmappedPorts       UnmappedPortPolicy
+	StrictOpcodes       bool
+	UnimplementedOpcode UnimplementedOpcodeHook
+	Fault               error
+	RegisterLookup      [8]*memory.Register
+	RegisterPairLookup  [4]*memory.RegisterPair
+	interruptRequest    []uint8
+	interruptDelay      bool
 }
 
 // Init must be called before using the CPU. This method initializes pointers and other elements necessary for the CPU to function correctly.
+// The registers and flags are cleared and the CPU is Reset, so execution begins at 0x0000 with interrupts disabled.
+// Use PowerOn to start from some other register and memory state.
 func (cpu *CPU) Init() {
-	cpu.InterruptsEnabled = true
 	cpu.A = *memory.NewRegister(0)
 	cpu.BC = *memory.NewRegisterPair(0, 0)
 	cpu.DE = *memory.NewRegisterPair(0, 0)
@@ -50,7 +65,9 @@ func (cpu *CPU) Init() {
 	cpu.L = &cpu.HL.Low
 	cpu.W = &cpu.WZ.High
 	cpu.Z = &cpu.WZ.Low
-	cpu.Memory = make([]uint8, 16384) // 16KB
+	if cpu.Memory == nil {
+		cpu.Memory = memory.NewRAM() // 64KB
+	}
 
 	cpu.RegisterLookup[0] = cpu.B
 	cpu.RegisterLookup[1] = cpu.C
@@ -65,31 +82,60 @@ func (cpu *CPU) Init() {
 	cpu.RegisterPairLookup[1] = &cpu.DE
 	cpu.RegisterPairLookup[2] = &cpu.HL
 	cpu.RegisterPairLookup[3] = &cpu.SP
-}
 
-// StandardInstructionCycle increments the Program Counter and executes the next instruction
-func (cpu *CPU) StandardInstructionCycle() {
-	cpu.exec(OpCode(cpu.Memory[cpu.ProgramCounter]))
+	cpu.Reset()
 }
 
-// InterruptInstructionCycle disables the InterruptsEnabled flag, reads an OpCode off the DataBus
-// and executes that OpCode and re-enables the InterruptsEnabled flag. The ProgramCounter is not
-// incremented prior to executing the OpCode.
-func (cpu *CPU) InterruptInstructionCycle() {
-	var interruptCmd uint8
-	cpu.DataBus.Read8(&interruptCmd)
+// StandardInstructionCycle increments the Program Counter and executes the next instruction. If an interrupt
+// is pending and can be accepted, the interrupt instruction cycle is executed instead. It returns the number of
+// T-states consumed, which are also added to Cycles. Each instruction that executes is counted in Instructions.
+// While the CPU is halted or stopped by a Fault, no instruction is fetched and no cycles are consumed. If the
+// memory bus refuses an access, the instruction completes without it and the CPU stops with a BusFaultError.
+func (cpu *CPU) StandardInstructionCycle() int {
+	if cpu.Fault != nil {
+		return 0
+	}
+
+	// A fault left by an access from outside the CPU, such as a host writing to ROM, is not the instruction's, so
+	// discard it before fetching
+	memory.TakeFault(cpu.Memory)
 
-	cpu.DisableInterrupts()
-	cpu.exec(OpCode(interruptCmd))
-	cpu.EnableInterrupts()
+	address := cpu.ProgramCounter
+	defer cpu.checkBusFault(address)
+
+	var cycles int
+	if cpu.interruptAcceptable() {
+		cycles = cpu.InterruptInstructionCycle()
+	} else {
+		cpu.interruptDelay = false
+
+		if cpu.Halted {
+			return 0
+		}
+		cycles = cpu.exec(OpCode(cpu.Memory.Read(cpu.ProgramCounter)))
+	}
+
+	if cycles > 0 {
+		cpu.Instructions++
+	}
+	return cycles
 }
 
-// exec executes the provided opcode
-func (cpu *CPU) exec(opcode OpCode) {
+// exec executes the provided opcode and returns the number of T-states it consumed
+func (cpu *CPU) exec(opcode OpCode) int {
+	var taken bool
+
+	opcode, ok := cpu.resolveOpCode(opcode)
+	if !ok {
+		return cpu.unimplemented(opcode)
+	}
+
 	switch opcode {
 	case NOP:
 		cpu.ProgramCounter += 1
 		break
+	case HLT:
+		cpu.Halt()
 	case CALL:
 		cpu.Call()
 	case RST0, RST1, RST2, RST3, RST4, RST5, RST6, RST7:
@@ -237,68 +283,77 @@ func (cpu *CPU) exec(opcode OpCode) {
 	case CMPM:
 		cpu.CompareMemory()
 	case RNZ:
-		cpu.executeReturnIfTrue(!cpu.ALU.IsZero())
+		taken = cpu.executeReturnIfTrue(!cpu.ALU.IsZero())
 	case CNZ:
-		cpu.executeCallIfTrue(!cpu.ALU.IsZero())
+		taken = cpu.executeCallIfTrue(!cpu.ALU.IsZero())
 	case RZ:
-		cpu.executeReturnIfTrue(cpu.ALU.IsZero())
+		taken = cpu.executeReturnIfTrue(cpu.ALU.IsZero())
 	case CZ:
-		cpu.executeCallIfTrue(cpu.ALU.IsZero())
+		taken = cpu.executeCallIfTrue(cpu.ALU.IsZero())
 	case ACI:
 		cpu.AddImmediateWithCarry()
 	case RNC:
-		cpu.executeReturnIfTrue(!cpu.ALU.IsCarry())
+		taken = cpu.executeReturnIfTrue(!cpu.ALU.IsCarry())
 	case OUT:
 		cpu.Output()
 	case CNC:
-		cpu.executeCallIfTrue(!cpu.ALU.IsCarry())
+		taken = cpu.executeCallIfTrue(!cpu.ALU.IsCarry())
 	case SUI:
 		cpu.SubtractImmediate()
 	case RC:
-		cpu.executeReturnIfTrue(cpu.ALU.IsCarry())
+		taken = cpu.executeReturnIfTrue(cpu.ALU.IsCarry())
 	case IN:
 		cpu.Input()
 	case CC:
-		cpu.executeCallIfTrue(cpu.ALU.IsCarry())
+		taken = cpu.executeCallIfTrue(cpu.ALU.IsCarry())
 	case SBI:
 		cpu.SubtractImmediateWithBorrow()
 	case RPO:
-		cpu.executeReturnIfTrue(!cpu.ALU.IsParity()) // Parity ODD
+		taken = cpu.executeReturnIfTrue(!cpu.ALU.IsParity()) // Parity ODD
 	case XTHL:
 		cpu.ExchangeStackTopWithHandL()
 	case CPO:
-		cpu.executeCallIfTrue(!cpu.ALU.IsParity()) // Parity ODD
+		taken = cpu.executeCallIfTrue(!cpu.ALU.IsParity()) // Parity ODD
 	case ANI:
 		cpu.AndImmediate()
 	case RPE:
-		cpu.executeReturnIfTrue(cpu.ALU.IsParity())
+		taken = cpu.executeReturnIfTrue(cpu.ALU.IsParity())
 	case PCHL:
 		cpu.MoveHandLtoPC()
 	case XCHG:
 		cpu.ExchangeHandLWithDAndE()
 	case CPE:
-		cpu.executeCallIfTrue(cpu.ALU.IsParity())
+		taken = cpu.executeCallIfTrue(cpu.ALU.IsParity())
 	case XRI:
 		cpu.XOrImmediate()
 	case RP:
-		cpu.executeReturnIfTrue(!cpu.ALU.IsSign())
+		taken = cpu.executeReturnIfTrue(!cpu.ALU.IsSign())
 	case DI:
 		cpu.DisableInterrupts()
 	case CP:
-		cpu.executeCallIfTrue(!cpu.ALU.IsSign())
+		taken = cpu.executeCallIfTrue(!cpu.ALU.IsSign())
 	case ORI:
 		cpu.OrImmediate()
 	case RM:
-		cpu.executeReturnIfTrue(cpu.ALU.IsSign())
+		taken = cpu.executeReturnIfTrue(cpu.ALU.IsSign())
 	case SPHL:
 		cpu.MoveHLToSP()
 	case EI:
 		cpu.EnableInterrupts()
 	case CM:
-		cpu.executeCallIfTrue(cpu.ALU.IsSign())
+		taken = cpu.executeCallIfTrue(cpu.ALU.IsSign())
 	case CPI:
 		cpu.CompareImmediate()
+	default:
+		return cpu.unimplemented(opcode)
+	}
+
+	cycles := int(instructionCycles[opcode])
+	if taken {
+		cycles += conditionalBranchCycles
 	}
+	cpu.Cycles += uint64(cycles)
+	return cycles
 }
 
 func (cpu *CPU) getOpCodeRegisterPair(opcode OpCode) *memory.RegisterPair {
@@ -317,7 +372,7 @@ func (cpu *CPU) getOpCodeRegisterSource(opcode OpCode) *memory.Register {
 }
 
 func (cpu *CPU) getJumpAddress() uint16 {
-	return (uint16(cpu.Memory[cpu.ProgramCounter+2]) << 8) | uint16(cpu.Memory[cpu.ProgramCounter+1])
+	return (uint16(cpu.Memory.Read(cpu.ProgramCounter+2)) << 8) | uint16(cpu.Memory.Read(cpu.ProgramCounter+1))
 }
 
 func (cpu *CPU) executeJumpIfTrue(condition bool) {
@@ -328,18 +383,20 @@ func (cpu *CPU) executeJumpIfTrue(condition bool) {
 	}
 }
 
-func (cpu *CPU) executeCallIfTrue(condition bool) {
+func (cpu *CPU) executeCallIfTrue(condition bool) bool {
 	if condition {
 		cpu.Call()
 	} else {
 		cpu.ProgramCounter += 3
 	}
+	return condition
 }
 
-func (cpu *CPU) executeReturnIfTrue(condition bool) {
+func (cpu *CPU) executeReturnIfTrue(condition bool) bool {
 	if condition {
 		cpu.Return()
 	} else {
 		cpu.ProgramCounter += 1
 	}
+	return condition
 }
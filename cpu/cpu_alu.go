@@ -15,7 +15,7 @@ func (cpu *CPU) AddRegister(r *memory.Register) {
 // AddImmediate implements the ADI data instruction. The content of the second byte of the instruction is added
 // to the content of the accumulator. The result is placed in the accumulator.
 func (cpu *CPU) AddImmediate() {
-	cpu.ALU.AddImmediate(cpu.Memory[cpu.ProgramCounter+1])
+	cpu.ALU.AddImmediate(cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
@@ -26,7 +26,7 @@ func (cpu *CPU) AddMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
 
-	addend := cpu.Memory[memoryAddress]
+	addend := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.AddImmediate(addend)
 	cpu.ProgramCounter += 1
 }
@@ -34,7 +34,7 @@ func (cpu *CPU) AddMemory() {
 // AddImmediateWithCarry implements the ACI data instruction. The content of the second byte of the instruction and
 // the content of the CY flag are added to the contents of the accumulator. The result is placed in the accumulator.
 func (cpu *CPU) AddImmediateWithCarry() {
-	cpu.ALU.AddImmediateWithCarry(cpu.Memory[cpu.ProgramCounter+1])
+	cpu.ALU.AddImmediateWithCarry(cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
@@ -45,7 +45,7 @@ func (cpu *CPU) AddMemoryWithCarry() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
 
-	addend := cpu.Memory[memoryAddress]
+	addend := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.AddImmediateWithCarry(addend)
 	cpu.ProgramCounter += 1
 }
@@ -83,7 +83,7 @@ func (cpu *CPU) SubtractMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
 
-	subtrahend := cpu.Memory[memoryAddress]
+	subtrahend := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.SubImmediate(subtrahend)
 	cpu.ProgramCounter += 1
 }
@@ -91,7 +91,7 @@ func (cpu *CPU) SubtractMemory() {
 // SubtractImmediate implements the SUI instruction. (A) <- (A) - (byte 2), The content of the second byte of the
 // instruction is subtracted from the content of the accumulator. The result is placed in the accumulator.
 func (cpu *CPU) SubtractImmediate() {
-	cpu.ALU.SubImmediate(cpu.Memory[cpu.ProgramCounter+1])
+	cpu.ALU.SubImmediate(cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
@@ -99,7 +99,7 @@ func (cpu *CPU) SubtractImmediate() {
 // byte of the instruction and the contents of the CY flag are both subtracted from the accumulator. The result is
 // placed in the accumulator.
 func (cpu *CPU) SubtractImmediateWithBorrow() {
-	cpu.ALU.SubImmediateWithBorrow(cpu.Memory[cpu.ProgramCounter+1])
+	cpu.ALU.SubImmediateWithBorrow(cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
@@ -129,7 +129,7 @@ func (cpu *CPU) SubtractMemoryWithBorrow() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
 
-	subtrahend := cpu.Memory[memoryAddress]
+	subtrahend := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.SubImmediateWithBorrow(subtrahend)
 	cpu.ProgramCounter += 1
 }
@@ -157,7 +157,7 @@ func (cpu *CPU) IncrementRegisterPair(rp *memory.RegisterPair) {
 func (cpu *CPU) IncrementMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	cpu.Memory[memoryAddress] = cpu.ALU.Increment(cpu.Memory[memoryAddress])
+	cpu.Memory.Write(memoryAddress, cpu.ALU.Increment(cpu.Memory.Read(memoryAddress)))
 	cpu.ProgramCounter += 1
 }
 
@@ -184,7 +184,7 @@ func (cpu *CPU) DecrementRegisterPair(rp *memory.RegisterPair) {
 func (cpu *CPU) DecrementMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	cpu.Memory[memoryAddress] = cpu.ALU.Decrement(cpu.Memory[memoryAddress])
+	cpu.Memory.Write(memoryAddress, cpu.ALU.Decrement(cpu.Memory.Read(memoryAddress)))
 	cpu.ProgramCounter += 1
 }
 
@@ -222,11 +222,10 @@ func (cpu *CPU) RotateLeftThroughCarry() {
 
 // AndImmediate implements the ANI data instruction. (A) <- (A) /\ (byte 2). The content of the second byte of the
 // instruction is logically anded with the contents of the accumulator. The result is placed in the accumulator.
-// The CY and AC flags are cleared.
+// The CY flag is cleared and the AC flag is set to bit 3 of the logical OR of the two operands.
 func (cpu *CPU) AndImmediate() {
-	operand := cpu.Memory[cpu.ProgramCounter+1]
+	operand := cpu.Memory.Read(cpu.ProgramCounter+1)
 	cpu.ALU.AndAccumulator(operand)
-	cpu.ALU.ClearAuxiliaryCarry()
 	cpu.ProgramCounter += 2
 }
 
@@ -245,7 +244,7 @@ func (cpu *CPU) AndRegister(r *memory.Register) {
 func (cpu *CPU) AndMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	cpu.ALU.AndAccumulator(cpu.Memory[memoryAddress])
+	cpu.ALU.AndAccumulator(cpu.Memory.Read(memoryAddress))
 	cpu.ProgramCounter += 1
 }
 
@@ -253,7 +252,7 @@ func (cpu *CPU) AndMemory() {
 // instruction is inclusive-OR'd with the content of the accumulator. The result is placed in the accumulator.
 // The CY and AC flags are cleared.
 func (cpu *CPU) OrImmediate() {
-	operand := cpu.Memory[cpu.ProgramCounter+1]
+	operand := cpu.Memory.Read(cpu.ProgramCounter+1)
 	cpu.ALU.OrAccumulator(operand)
 	cpu.ProgramCounter += 2
 }
@@ -271,7 +270,7 @@ func (cpu *CPU) OrRegister(r *memory.Register) {
 func (cpu *CPU) OrMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	operand := cpu.Memory[memoryAddress]
+	operand := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.OrAccumulator(operand)
 	cpu.ProgramCounter += 1
 }
@@ -280,7 +279,7 @@ func (cpu *CPU) OrMemory() {
 // instruction is exclusive-O R'd with the content of the accumulator. The result is placed in the accumulator. The
 // CY and AC flags are cleared.
 func (cpu *CPU) XOrImmediate() {
-	operand := cpu.Memory[cpu.ProgramCounter+1]
+	operand := cpu.Memory.Read(cpu.ProgramCounter+1)
 	cpu.ALU.XOrAccumulator(operand)
 	cpu.ProgramCounter += 2
 }
@@ -300,7 +299,7 @@ func (cpu *CPU) XOrRegister(r *memory.Register) {
 func (cpu *CPU) XOrMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	cpu.ALU.XOrAccumulator(cpu.Memory[memoryAddress])
+	cpu.ALU.XOrAccumulator(cpu.Memory.Read(memoryAddress))
 	cpu.ProgramCounter += 1
 }
 
@@ -361,7 +360,7 @@ func (cpu *CPU) CompareRegister(r *memory.Register) {
 // instruction is subtracted from the accumulator. The condition flags are set by the result of the subtraction.
 // The Z flag is set to 1 if (A) = (byte 2). The CY flag is set to 1 if (A) < (byte 2).
 func (cpu *CPU) CompareImmediate() {
-	operand := cpu.Memory[cpu.ProgramCounter+1]
+	operand := cpu.Memory.Read(cpu.ProgramCounter+1)
 	cpu.ALU.CompareAccumulator(operand)
 	cpu.ProgramCounter += 2
 }
@@ -373,7 +372,7 @@ func (cpu *CPU) CompareImmediate() {
 func (cpu *CPU) CompareMemory() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	operand := cpu.Memory[memoryAddress]
+	operand := cpu.Memory.Read(memoryAddress)
 	cpu.ALU.CompareAccumulator(operand)
 	cpu.ProgramCounter += 1
 }
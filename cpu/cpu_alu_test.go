@@ -32,7 +32,7 @@ func TestCPU_AddImmediate(t *testing.T) {
 
 	cpu := &CPU{
 		ALU:            mALU,
-		Memory:         []uint8{0, 1},
+		Memory:         memory.RAM{0, 1},
 		ProgramCounter: 0,
 	}
 	cpu.AddImmediate()
@@ -47,7 +47,7 @@ func TestCPU_AddImmediateWithCarry(t *testing.T) {
 
 	cpu := &CPU{
 		ALU:            mALU,
-		Memory:         []uint8{0, 1},
+		Memory:         memory.RAM{0, 1},
 		ProgramCounter: 0,
 	}
 	cpu.AddImmediateWithCarry()
@@ -191,7 +191,7 @@ func TestCPU_IncrementMemory(t *testing.T) {
 	mALU.EXPECT().Increment(uint8(1)).Return(uint8(2))
 
 	hl := memory.NewRegisterPair(0, 1)
-	memory := []uint8{0, 1}
+	memory := memory.RAM{0, 1}
 
 	cpu := &CPU{
 		ALU:    mALU,
@@ -261,7 +261,7 @@ func TestCPU_DecrementMemory(t *testing.T) {
 	mALU.EXPECT().Decrement(uint8(1)).Return(uint8(0))
 
 	hl := memory.NewRegisterPair(0, 1)
-	memory := []uint8{0, 1}
+	memory := memory.RAM{0, 1}
 
 	cpu := &CPU{
 		ALU:    mALU,
@@ -334,7 +334,6 @@ func TestCPU_AndImmediate(t *testing.T) {
 
 	mALU := alumock.NewMockALU(ctrl)
 	mALU.EXPECT().AndAccumulator(uint8(0x01))
-	mALU.EXPECT().ClearAuxiliaryCarry()
 
 	cpu := makeCPU(0x00, []uint8{uint8(ANI), 0x01}, 0x00)
 	cpu.ALU = mALU
@@ -365,7 +364,7 @@ func TestCPU_AndMemory(t *testing.T) {
 	mALU.EXPECT().AndAccumulator(uint8(1))
 
 	hl := memory.NewRegisterPair(0, 1)
-	memory := []uint8{0, 1}
+	memory := memory.RAM{0, 1}
 
 	cpu := &CPU{
 		HL:     *hl,
@@ -439,7 +438,7 @@ func TestCPU_XOrMemory(t *testing.T) {
 	mALU.EXPECT().XOrAccumulator(uint8(1))
 
 	hl := memory.NewRegisterPair(0, 1)
-	memory := []uint8{0, 1}
+	memory := memory.RAM{0, 1}
 
 	cpu := &CPU{
 		ALU:    mALU,
@@ -1,6 +1,9 @@
 package cpu
 
-import "github.com/cbush06/intel8080emulator/memory"
+import (
+	"github.com/cbush06/intel8080emulator/memory"
+	"testing"
+)
 
 func makeCPU(programCounter uint16, memoryBuffer []uint8, stackPointer uint16) *CPU {
 	rp := memory.NewRegisterPair(0, 0)
@@ -8,7 +11,83 @@ func makeCPU(programCounter uint16, memoryBuffer []uint8, stackPointer uint16) *
 
 	return &CPU{
 		ProgramCounter: programCounter,
-		Memory:         memoryBuffer,
+		Memory:         memory.RAM(memoryBuffer),
 		SP:             *rp,
 	}
 }
+
+func TestCPU_StandardInstructionCycleHalted(t *testing.T) {
+	cpu := makeCPU(0, []uint8{uint8(HLT), uint8(NOP), uint8(NOP)}, 0)
+
+	cpu.StandardInstructionCycle()
+	if !cpu.Halted {
+		t.Error("Expected CPU to be halted after HLT but was not")
+	}
+
+	// Further cycles must not fetch instructions while halted
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 1 {
+		t.Errorf("Expected PC to remain 1 while halted but was %d", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_InitFullAddressSpace(t *testing.T) {
+	cpu := new(CPU)
+	cpu.Init()
+	cpu.SP.Write16(0xFFFF)
+	cpu.BC.Write16(0xABCD)
+
+	cpu.Push(&cpu.BC)
+
+	if cpu.Memory.Read(0xFFFE) != 0xAB || cpu.Memory.Read(0xFFFD) != 0xCD {
+		t.Errorf("Expected 0xABCD to be pushed below 0xFFFF but found 0x%X%X", cpu.Memory.Read(0xFFFE), cpu.Memory.Read(0xFFFD))
+	}
+}
+
+func TestCPU_InitKeepsMemoryBus(t *testing.T) {
+	ram := memory.NewRAM()
+	cpu := &CPU{Memory: ram}
+	cpu.Init()
+
+	cpu.Memory.Write(0x8000, 0xAB)
+	if ram[0x8000] != 0xAB {
+		t.Error("Expected Init to keep the provided memory bus but it was replaced")
+	}
+}
+
+func TestCPU_OperandFetchWraparound(t *testing.T) {
+	// JMP at 0xFFFE takes its high-order address byte from 0x0000
+	cpu := makeInterruptCPU(nil)
+	cpu.ProgramCounter = 0xFFFE
+	cpu.Memory.Write(0xFFFE, uint8(JMP))
+	cpu.Memory.Write(0xFFFF, 0x34)
+	cpu.Memory.Write(0x0000, 0x12)
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x1234 {
+		t.Errorf("Expected JMP straddling 0xFFFF to jump to 0x1234 but PC was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_ProgramCounterWraparound(t *testing.T) {
+	// LXI B at 0xFFFF reads its operand from 0x0000 and 0x0001 and continues at 0x0002
+	cpu := makeInterruptCPU(nil)
+	cpu.ProgramCounter = 0xFFFF
+	cpu.Memory.Write(0xFFFF, uint8(LXIB))
+	cpu.Memory.Write(0x0000, 0xCD)
+	cpu.Memory.Write(0x0001, 0xAB)
+
+	cpu.StandardInstructionCycle()
+
+	var bc uint16
+	cpu.BC.Read16(&bc)
+	if bc != 0xABCD {
+		t.Errorf("Expected BC to be 0xABCD but was 0x%X", bc)
+	}
+
+	if cpu.ProgramCounter != 0x0002 {
+		t.Errorf("Expected PC to wrap to 0x0002 but was 0x%X", cpu.ProgramCounter)
+	}
+}
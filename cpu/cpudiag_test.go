@@ -0,0 +1,24 @@
+package cpu_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/conformance"
+)
+
+func TestCPU_CPUDiag(t *testing.T) {
+	program, err := ioutil.ReadFile("../cpudiag.bin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := conformance.Run(conformance.Diagnostic, program, 1000000)
+	if err != nil {
+		t.Fatalf("Expected cpudiag to run to completion but got %v; output: %q", err, result.Output)
+	}
+
+	if !result.Passed() {
+		t.Errorf("Expected cpudiag to report \"CPU IS OPERATIONAL\" but it wrote %q", result.Output)
+	}
+}
@@ -0,0 +1,320 @@
+// Package cputest runs short assembly programs on a CPU for unit tests. A test writes its program as source,
+// optionally sets registers, flags and memory, runs it to HLT and checks the result with chained assertions:
+//
+//	cputest.Assemble(t, `
+//		MVI	A,12H
+//		ADI	0F0H
+//		HLT`).
+//		Run().
+//		Register(cputest.A, 0x02).
+//		Flags(cputest.Carry).
+//		Cycles(7 + 7 + 7)
+//
+// The program is assembled with package asm, so it may use labels, expressions and directives. It is loaded at the
+// lowest address assembled, and starts at the operand of its END, or at that address. Failed assertions are reported
+// with t.Errorf and the test continues; a program that does not assemble, faults or fails to halt ends the test with
+// t.Fatalf.
+package cputest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/alu"
+	"github.com/cbush06/intel8080emulator/asm"
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// DefaultLimit is the number of instructions a program may execute before Run gives up waiting for HLT.
+const DefaultLimit = 100000
+
+// Register names an 8-bit register.
+type Register int
+
+const (
+	A Register = iota
+	B
+	C
+	D
+	E
+	H
+	L
+)
+
+func (r Register) String() string {
+	return [...]string{"A", "B", "C", "D", "E", "H", "L"}[r]
+}
+
+// Pair names a 16-bit register pair or register.
+type Pair int
+
+const (
+	BC Pair = iota
+	DE
+	HL
+	SP
+	PC
+)
+
+func (rp Pair) String() string {
+	return [...]string{"BC", "DE", "HL", "SP", "PC"}[rp]
+}
+
+// Flag is a set of condition flags, with the bit of each flag in the processor status word.
+type Flag uint8
+
+const (
+	Carry          Flag = 0x01
+	Parity         Flag = 0x04
+	AuxiliaryCarry Flag = 0x10
+	Zero           Flag = 0x40
+	Sign           Flag = 0x80
+)
+
+// String lists the flags in the set, as in "Z P", or returns "none".
+func (f Flag) String() string {
+	var names []string
+	for _, flag := range []struct {
+		flag Flag
+		name string
+	}{{Sign, "S"}, {Zero, "Z"}, {AuxiliaryCarry, "AC"}, {Parity, "P"}, {Carry, "CY"}} {
+		if f&flag.flag != 0 {
+			names = append(names, flag.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, " ")
+}
+
+// Program is an assembled program loaded into a CPU, ready to run.
+type Program struct {
+	t       testing.TB
+	CPU     *cpu.CPU
+	Program *asm.Program
+	limit   uint64
+}
+
+// Assemble assembles source and loads it into a new CPU with 64 KiB of RAM. The test fails immediately if source
+// does not assemble.
+func Assemble(t testing.TB, source string) *Program {
+	t.Helper()
+
+	program, err := asm.Assemble("program.asm", []byte(source))
+	if err != nil {
+		t.Fatalf("Expected the program to assemble but got %v", err)
+	}
+
+	c := new(cpu.CPU)
+	c.Init()
+	for i, b := range program.Code {
+		c.Memory.Write(program.Origin+uint16(i), b)
+	}
+	c.ProgramCounter = program.Origin
+	if program.HasEntry {
+		c.ProgramCounter = program.Entry
+	}
+
+	return &Program{t: t, CPU: c, Program: program, limit: DefaultLimit}
+}
+
+// Run assembles source and runs it to HLT. It is shorthand for Assemble(t, source).Run().
+func Run(t testing.TB, source string) *Result {
+	t.Helper()
+	return Assemble(t, source).Run()
+}
+
+// SetRegister sets an 8-bit register before the program runs.
+func (p *Program) SetRegister(r Register, v uint8) *Program {
+	register(p.CPU, r).Write8(v)
+	return p
+}
+
+// SetPair sets a register pair, the stack pointer or the program counter before the program runs.
+func (p *Program) SetPair(rp Pair, v uint16) *Program {
+	switch rp {
+	case PC:
+		p.CPU.ProgramCounter = v
+	default:
+		pair(p.CPU, rp).Write16(v)
+	}
+	return p
+}
+
+// SetFlags sets the flags in f and clears the others before the program runs.
+func (p *Program) SetFlags(f Flag) *Program {
+	p.CPU.ALU.ApplyStatusWord(uint8(f))
+	return p
+}
+
+// Poke stores bytes in memory from addr before the program runs.
+func (p *Program) Poke(addr uint16, bytes ...uint8) *Program {
+	for i, b := range bytes {
+		p.CPU.Memory.Write(addr+uint16(i), b)
+	}
+	return p
+}
+
+// AttachDevice attaches an I/O device to a port before the program runs.
+func (p *Program) AttachDevice(port uint8, device cpu.IODevice) *Program {
+	p.CPU.AttachDevice(port, device)
+	return p
+}
+
+// Limit sets the number of instructions the program may execute before Run gives up waiting for HLT.
+func (p *Program) Limit(instructions uint64) *Program {
+	p.limit = instructions
+	return p
+}
+
+// Run executes the program until it halts. The test fails immediately if the CPU faults or the program does not
+// halt within the instruction limit.
+func (p *Program) Run() *Result {
+	p.t.Helper()
+
+	c := p.CPU
+	for !c.Halted && c.Fault == nil && c.Instructions < p.limit {
+		c.StandardInstructionCycle()
+	}
+
+	switch {
+	case c.Fault != nil:
+		p.t.Fatalf("Expected the program to halt but the CPU faulted: %v", c.Fault)
+	case !c.Halted:
+		p.t.Fatalf("Expected the program to halt within %d instructions but it was still running at 0x%04X",
+			p.limit, c.ProgramCounter)
+	}
+	return &Result{t: p.t, CPU: c}
+}
+
+// Result is the state of the CPU after a program halted. Each assertion reports a failure with t.Errorf and returns
+// the Result, so that assertions can be chained.
+type Result struct {
+	t   testing.TB
+	CPU *cpu.CPU
+}
+
+// Register asserts that an 8-bit register holds v.
+func (r *Result) Register(reg Register, v uint8) *Result {
+	r.t.Helper()
+
+	var actual uint8
+	register(r.CPU, reg).Read8(&actual)
+	if actual != v {
+		r.t.Errorf("Expected register %s to be 0x%02X but got 0x%02X", reg, v, actual)
+	}
+	return r
+}
+
+// Pair asserts that a register pair, the stack pointer or the program counter holds v. The program counter is the
+// address following the HLT.
+func (r *Result) Pair(rp Pair, v uint16) *Result {
+	r.t.Helper()
+
+	actual := r.CPU.ProgramCounter
+	if rp != PC {
+		pair(r.CPU, rp).Read16(&actual)
+	}
+	if actual != v {
+		r.t.Errorf("Expected %s to be 0x%04X but got 0x%04X", rp, v, actual)
+	}
+	return r
+}
+
+// Flags asserts that exactly the flags in f are set.
+func (r *Result) Flags(f Flag) *Result {
+	r.t.Helper()
+
+	if actual := r.flags(); actual != f {
+		r.t.Errorf("Expected flags %s but got %s", f, actual)
+	}
+	return r
+}
+
+// Set asserts that the flags in f are set, whatever the others are.
+func (r *Result) Set(f Flag) *Result {
+	r.t.Helper()
+
+	if actual := r.flags(); actual&f != f {
+		r.t.Errorf("Expected flags %s to be set but got %s", f, actual)
+	}
+	return r
+}
+
+// Clear asserts that the flags in f are clear, whatever the others are.
+func (r *Result) Clear(f Flag) *Result {
+	r.t.Helper()
+
+	if actual := r.flags(); actual&f != 0 {
+		r.t.Errorf("Expected flags %s to be clear but got %s", f, actual)
+	}
+	return r
+}
+
+func (r *Result) flags() Flag {
+	return Flag(r.CPU.ALU.CreateStatusWord() & alu.StatusWordFlags)
+}
+
+// Memory asserts that memory from addr holds bytes.
+func (r *Result) Memory(addr uint16, bytes ...uint8) *Result {
+	r.t.Helper()
+
+	for i, b := range bytes {
+		a := addr + uint16(i)
+		if actual := memory.Peek(r.CPU.Memory, a); actual != b {
+			r.t.Errorf("Expected memory at 0x%04X to be 0x%02X but got 0x%02X", a, b, actual)
+		}
+	}
+	return r
+}
+
+// Word asserts that memory at addr holds the little-endian word v.
+func (r *Result) Word(addr uint16, v uint16) *Result {
+	r.t.Helper()
+
+	actual := uint16(memory.Peek(r.CPU.Memory, addr+1))<<8 | uint16(memory.Peek(r.CPU.Memory, addr))
+	if actual != v {
+		r.t.Errorf("Expected the word at 0x%04X to be 0x%04X but got 0x%04X", addr, v, actual)
+	}
+	return r
+}
+
+// Cycles asserts that the program took n T-states, including the HLT.
+func (r *Result) Cycles(n uint64) *Result {
+	r.t.Helper()
+
+	if r.CPU.Cycles != n {
+		r.t.Errorf("Expected the program to take %d cycles but it took %d", n, r.CPU.Cycles)
+	}
+	return r
+}
+
+// Instructions asserts that the program executed n instructions, including the HLT.
+func (r *Result) Instructions(n uint64) *Result {
+	r.t.Helper()
+
+	if r.CPU.Instructions != n {
+		r.t.Errorf("Expected the program to execute %d instructions but it executed %d", n, r.CPU.Instructions)
+	}
+	return r
+}
+
+// InterruptsEnabled asserts whether interrupts are enabled.
+func (r *Result) InterruptsEnabled(enabled bool) *Result {
+	r.t.Helper()
+
+	if r.CPU.InterruptsEnabled != enabled {
+		r.t.Errorf("Expected interrupts enabled to be %v but got %v", enabled, r.CPU.InterruptsEnabled)
+	}
+	return r
+}
+
+func register(c *cpu.CPU, r Register) *memory.Register {
+	return [...]*memory.Register{&c.A, c.B, c.C, c.D, c.E, c.H, c.L}[r]
+}
+
+func pair(c *cpu.CPU, rp Pair) *memory.RegisterPair {
+	return [...]*memory.RegisterPair{&c.BC, &c.DE, &c.HL, &c.SP}[rp]
+}
@@ -0,0 +1,166 @@
+package cputest
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them. Fatalf ends the goroutine, as it does
+// for a real test.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatal  string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.fatal = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// record runs f with a recorder in its own goroutine, so that Fatalf can end it.
+func record(t *testing.T, f func(tb testing.TB)) *recorder {
+	r := &recorder{TB: t}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(r)
+	}()
+	<-done
+	return r
+}
+
+func TestRun(t *testing.T) {
+	Run(t, `
+	MVI	A,12H
+	ADI	0F0H
+	HLT`).
+		Register(A, 0x02).
+		Flags(Carry).
+		Set(Carry).
+		Clear(Zero|Sign).
+		Pair(PC, 0x0005).
+		Cycles(21).
+		Instructions(3)
+}
+
+func TestRun_LabelsAndMemory(t *testing.T) {
+	Run(t, `
+	ORG	100H
+START:	LXI	H,DATA
+	MOV	A,M
+	INX	H
+	ADD	M
+	STA	SUM
+	LHLD	DATA
+	SHLD	COPY
+	HLT
+DATA:	DB	3,4
+SUM:	DS	1
+COPY:	DS	2
+	END	START`).
+		Register(A, 7).
+		Pair(HL, 0x0403).
+		Memory(0x0110, 3, 4, 7).
+		Word(0x0113, 0x0403)
+}
+
+func TestAssemble_Setup(t *testing.T) {
+	Assemble(t, "\tADC\tB\n\tPUSH\tD\n\tHLT\n").
+		SetRegister(A, 1).
+		SetRegister(B, 2).
+		SetPair(DE, 0xBEEF).
+		SetPair(SP, 0x2000).
+		SetFlags(Carry).
+		Poke(0x3000, 0xAA).
+		Run().
+		Register(A, 4).
+		Pair(SP, 0x1FFE).
+		Word(0x1FFE, 0xBEEF).
+		Memory(0x3000, 0xAA)
+}
+
+func TestAssemble_Device(t *testing.T) {
+	device := &echo{}
+	Assemble(t, "\tIN\t1\n\tINR\tA\n\tOUT\t1\n\tHLT\n").
+		AttachDevice(1, device).
+		Run()
+
+	if device.out != 0x43 {
+		t.Errorf("Expected the device to receive 0x43 but got 0x%02X", device.out)
+	}
+}
+
+type echo struct {
+	out uint8
+}
+
+func (e *echo) In(port uint8) uint8 {
+	return 0x42
+}
+
+func (e *echo) Out(port uint8, v uint8) {
+	e.out = v
+}
+
+func TestResult_Failures(t *testing.T) {
+	r := record(t, func(tb testing.TB) {
+		Run(tb, "\tMVI\tA,1\n\tHLT\n").
+			Register(A, 2).
+			Pair(PC, 0).
+			Flags(Zero|Parity).
+			Memory(0, 0x3E, 0xFF).
+			Cycles(1).
+			Instructions(1).
+			InterruptsEnabled(true)
+	})
+
+	expected := []string{
+		"Expected register A to be 0x02 but got 0x01",
+		"Expected PC to be 0x0000 but got 0x0003",
+		"Expected flags Z P but got none",
+		"Expected memory at 0x0001 to be 0xFF but got 0x01",
+		"Expected the program to take 1 cycles but it took 14",
+		"Expected the program to execute 1 instructions but it executed 2",
+		"Expected interrupts enabled to be true but got false",
+	}
+	if strings.Join(r.errors, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected failures\n%s\nbut got\n%s", strings.Join(expected, "\n"), strings.Join(r.errors, "\n"))
+	}
+}
+
+func TestRun_Fatal(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"\tBAD\n", "Expected the program to assemble but got program.asm:1: unknown instruction BAD"},
+		{"LOOP:\tJMP\tLOOP\n", "Expected the program to halt within 100000 instructions but it was still running at 0x0000"},
+	}
+
+	for _, test := range tests {
+		r := record(t, func(tb testing.TB) {
+			Run(tb, test.source)
+		})
+		if r.fatal != test.expected {
+			t.Errorf("Expected %q to fail with %q but got %q", test.source, test.expected, r.fatal)
+		}
+	}
+}
+
+func TestFlag_String(t *testing.T) {
+	if s := (Sign | Zero | AuxiliaryCarry | Parity | Carry).String(); s != "S Z AC P CY" {
+		t.Errorf("Expected \"S Z AC P CY\" but got %q", s)
+	}
+	if s := Flag(0).String(); s != "none" {
+		t.Errorf("Expected \"none\" but got %q", s)
+	}
+}
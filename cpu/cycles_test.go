@@ -0,0 +1,134 @@
+package cpu
+
+import "testing"
+
+// datasheetCycles lists the T-state counts given for each instruction group in the Intel 8080 Microcomputer
+// Systems User's Manual. Conditional CALL and RET counts are listed as untaken/taken.
+var datasheetCycles = []struct {
+	name    string
+	opcodes []OpCode
+	cycles  int
+}{
+	{"MOV r1,r2", []OpCode{MOVAA, MOVAB, MOVAC, MOVAD, MOVAE, MOVAH, MOVAL, MOVBA, MOVBB, MOVBC, MOVBD, MOVBE, MOVBH,
+		MOVBL, MOVCA, MOVCB, MOVCC, MOVCD, MOVCE, MOVCH, MOVCL, MOVDA, MOVDB, MOVDC, MOVDD, MOVDE, MOVDH, MOVDL, MOVEA,
+		MOVEB, MOVEC, MOVED, MOVEE, MOVEH, MOVEL, MOVHA, MOVHB, MOVHC, MOVHD, MOVHE, MOVHH, MOVHL, MOVLA, MOVLB, MOVLC,
+		MOVLD, MOVLE, MOVLH, MOVLL}, 5},
+	{"MOV r,M", []OpCode{MOVAM, MOVBM, MOVCM, MOVDM, MOVEM, MOVHM, MOVLM}, 7},
+	{"MOV M,r", []OpCode{MOVMA, MOVMB, MOVMC, MOVMD, MOVME, MOVMH, MOVML}, 7},
+	{"MVI r", []OpCode{MVIA, MVIB, MVIC, MVID, MVIE, MVIH, MVIL}, 7},
+	{"MVI M", []OpCode{MVIM}, 10},
+	{"LXI", []OpCode{LXIB, LXID, LXIH, LXISP}, 10},
+	{"LDA/STA", []OpCode{LDA, STA}, 13},
+	{"LHLD/SHLD", []OpCode{LHLD, SHLD}, 16},
+	{"LDAX/STAX", []OpCode{LDAXB, LDAXD, STAXB, STAXD}, 7},
+	{"XCHG", []OpCode{XCHG}, 4},
+	{"ALU r", []OpCode{ADDA, ADDB, ADDC, ADDD, ADDE, ADDH, ADDL, ADCA, ADCB, ADCC, ADCD, ADCE, ADCH, ADCL, SUBA, SUBB,
+		SUBC, SUBD, SUBE, SUBH, SUBL, SBBA, SBBB, SBBC, SBBD, SBBE, SBBH, SBBL, ANAA, ANAB, ANAC, ANAD, ANAE, ANAH, ANAL,
+		XRAA, XRAB, XRAC, XRAD, XRAE, XRAH, XRAL, ORAA, ORAB, ORAC, ORAD, ORAE, ORAH, ORAL, CMPA, CMPB, CMPC, CMPD, CMPE,
+		CMPH, CMPL}, 4},
+	{"ALU M", []OpCode{ADDM, ADCM, SUBM, SBBM, ANAM, XRAM, ORAM, CMPM}, 7},
+	{"ALU immediate", []OpCode{ADI, ACI, SUI, SBI, ANI, XRI, ORI, CPI}, 7},
+	{"INR/DCR r", []OpCode{INRA, INRB, INRC, INRD, INRE, INRH, INRL, DCRA, DCRB, DCRC, DCRD, DCRE, DCRH, DCRL}, 5},
+	{"INR/DCR M", []OpCode{INRM, DCRM}, 10},
+	{"INX/DCX", []OpCode{INXB, INXD, INXH, INXSP, DCXB, DCXD, DCXH, DCXSP}, 5},
+	{"DAD", []OpCode{DADB, DADD, DADH, DADSP}, 10},
+	{"DAA", []OpCode{DAA}, 4},
+	{"Rotate", []OpCode{RLC, RRC, RAL, RAR}, 4},
+	{"CMA/CMC/STC", []OpCode{CMA, CMC, STC}, 4},
+	{"JMP/Jcc", []OpCode{JMP, JNZ, JZ, JNC, JC, JPO, JPE, JP, JM}, 10},
+	{"CALL", []OpCode{CALL}, 17},
+	{"RET", []OpCode{RET}, 10},
+	{"RST", []OpCode{RST0, RST1, RST2, RST3, RST4, RST5, RST6, RST7}, 11},
+	{"PCHL", []OpCode{PCHL}, 5},
+	{"PUSH", []OpCode{PUSHB, PUSHD, PUSHH, PUSHPSW}, 11},
+	{"POP", []OpCode{POPB, POPD, POPH, POPPSW}, 10},
+	{"XTHL", []OpCode{XTHL}, 18},
+	{"SPHL", []OpCode{SPHL}, 5},
+	{"IN/OUT", []OpCode{IN, OUT}, 10},
+	{"EI/DI", []OpCode{EI, DI}, 4},
+	{"HLT", []OpCode{HLT}, 7},
+	{"NOP", []OpCode{NOP}, 4},
+}
+
+var conditionalCalls = []OpCode{CNZ, CZ, CNC, CC, CPO, CPE, CP, CM}
+var conditionalReturns = []OpCode{RNZ, RZ, RNC, RC, RPO, RPE, RP, RM}
+
+func makeCycleCPU(opcode OpCode) *CPU {
+	cpu := new(CPU)
+	cpu.Init()
+	cpu.ProgramCounter = 0x0100
+	cpu.SP.Write16(0x8000)
+	cpu.HL.Write16(0x4000)
+	cpu.Memory.Write(0x0100, uint8(opcode))
+	return cpu
+}
+
+func TestCPU_InstructionCycles(t *testing.T) {
+	for _, group := range datasheetCycles {
+		for _, opcode := range group.opcodes {
+			cpu := makeCycleCPU(opcode)
+
+			if cycles := cpu.StandardInstructionCycle(); cycles != group.cycles {
+				t.Errorf("%s: expected opcode 0x%02X to take %d cycles but took %d", group.name, uint8(opcode), group.cycles, cycles)
+			}
+
+			if cpu.Cycles != uint64(group.cycles) {
+				t.Errorf("%s: expected cycle counter to be %d after opcode 0x%02X but was %d", group.name, group.cycles, uint8(opcode), cpu.Cycles)
+			}
+		}
+	}
+}
+
+func TestCPU_ConditionalInstructionCycles(t *testing.T) {
+	var tests = []struct {
+		opcodes []OpCode
+		untaken int
+		taken   int
+	}{
+		{conditionalCalls, 11, 17},
+		{conditionalReturns, 5, 11},
+	}
+
+	for _, test := range tests {
+		for i, opcode := range test.opcodes {
+			// With every flag clear the even condition codes (NZ, NC, PO, P) are met; with every flag set the odd
+			// ones (Z, C, PE, M) are.
+			for _, flagsSet := range []bool{false, true} {
+				cpu := makeCycleCPU(opcode)
+				if flagsSet {
+					cpu.ALU.ApplyStatusWord(0xFF)
+				}
+
+				expected := test.untaken
+				if (i%2 == 1) == flagsSet {
+					expected = test.taken
+				}
+
+				if cycles := cpu.StandardInstructionCycle(); cycles != expected {
+					t.Errorf("Expected opcode 0x%02X to take %d cycles with flags set=%v but took %d", uint8(opcode), expected, flagsSet, cycles)
+				}
+			}
+		}
+	}
+}
+
+func TestCPU_CyclesAccumulate(t *testing.T) {
+	cpu := makeCycleCPU(MVIA)
+	cpu.Memory.Write(0x0102, uint8(CALL))
+	cpu.Memory.Write(0x0105, uint8(HLT))
+	cpu.Memory.Write(0x0103, 0x05)
+	cpu.Memory.Write(0x0104, 0x01)
+
+	for i := 0; i < 4; i++ {
+		cpu.StandardInstructionCycle()
+	}
+
+	if cpu.Cycles != 7+17+7 {
+		t.Errorf("Expected %d cycles but counted %d", 7+17+7, cpu.Cycles)
+	}
+
+	// The halted cycle fetches no instruction
+	if cpu.Instructions != 3 {
+		t.Errorf("Expected 3 instructions but counted %d", cpu.Instructions)
+	}
+}
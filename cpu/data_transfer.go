@@ -5,11 +5,11 @@ import (
 )
 
 // Input moves data that was placed on the eight bit bi-directional data bus by the specified
-// port to register A (the accumulator). Presumably, the system state will have switched
-// the WR pin to 1 (indicating a read operation).
+// port to register A (the accumulator). The byte is supplied by the IODevice attached to the
+// port or, if there is none, according to the CPU's UnmappedPorts policy.
 func (cpu *CPU) Input() {
 	var incomingData uint8
-	port := cpu.Memory[cpu.ProgramCounter+1]
+	port := cpu.Memory.Read(cpu.ProgramCounter+1)
 
 	// Write PORT selection to both high and low byte of AddressBus
 	// SEE: Wikipedia's Intel 8080 I/O Scheme: https://en.wikipedia.org/wiki/Intel_8080#Input/output_scheme
@@ -18,18 +18,22 @@ func (cpu *CPU) Input() {
 	// SEE: "I/O Addressing" in Intel 8080 System User's Manual (page 3-9)
 	// SEE: "I/O Port Decoder" and Example #1 of that section in Intel 8080 System User's Manual (page 5-149)
 
-	// Read DataBus values into register A (the accumulator)
-	cpu.DataBus.Read8(&incomingData)
+	if !cpu.readPort(port, &incomingData) {
+		return
+	}
+
+	// Place the device's byte on the DataBus and load it into register A (the accumulator)
+	cpu.DataBus.Write8(incomingData)
 	cpu.A.Write8(incomingData)
 	cpu.ProgramCounter += 2
 }
 
 // Output places the content of register A (the accumulator) on the eight-bit bi-directional data bus
-// for transmission to the specified port. Presumably, the system state will have switched
-// the WR pin to 0 (indicating a write operation).
+// for transmission to the specified port. The byte is delivered to the IODevice attached to the
+// port or, if there is none, handled according to the CPU's UnmappedPorts policy.
 func (cpu *CPU) Output() {
 	var outgoingData uint8
-	port := cpu.Memory[cpu.ProgramCounter+1]
+	port := cpu.Memory.Read(cpu.ProgramCounter+1)
 
 	// Write PORT selection to both high and low byte of AddressBus
 	// SEE: Wikipedia's Intel 8080 I/O Scheme: https://en.wikipedia.org/wiki/Intel_8080#Input/output_scheme
@@ -41,6 +45,10 @@ func (cpu *CPU) Output() {
 	// Write register A (the accumulator) values into DataBus
 	cpu.A.Read8(&outgoingData)
 	cpu.DataBus.Write8(outgoingData)
+
+	if !cpu.writePort(port, outgoingData) {
+		return
+	}
 	cpu.ProgramCounter += 2
 }
 
@@ -56,7 +64,7 @@ func (cpu *CPU) MoveRegister(r1 *memory.Register, r2 *memory.Register) {
 func (cpu *CPU) MoveFromMemory(r *memory.Register) {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	r.Write8(cpu.Memory[memoryAddress])
+	r.Write8(cpu.Memory.Read(memoryAddress))
 	cpu.ProgramCounter += 1
 }
 
@@ -64,13 +72,16 @@ func (cpu *CPU) MoveFromMemory(r *memory.Register) {
 func (cpu *CPU) MoveToMemory(r *memory.Register) {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	r.Read8(&cpu.Memory[memoryAddress])
+
+	var data uint8
+	r.Read8(&data)
+	cpu.Memory.Write(memoryAddress, data)
 	cpu.ProgramCounter += 1
 }
 
 // MoveImmediate implements MOV r, data. The data argument is moved to register r.
 func (cpu *CPU) MoveImmediate(r *memory.Register) {
-	r.Write8(cpu.Memory[cpu.ProgramCounter+1])
+	r.Write8(cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
@@ -79,15 +90,15 @@ func (cpu *CPU) MoveImmediate(r *memory.Register) {
 func (cpu *CPU) MoveToMemoryImmediate() {
 	var memoryAddress uint16
 	cpu.HL.Read16(&memoryAddress)
-	cpu.Memory[memoryAddress] = cpu.Memory[cpu.ProgramCounter+1]
+	cpu.Memory.Write(memoryAddress, cpu.Memory.Read(cpu.ProgramCounter+1))
 	cpu.ProgramCounter += 2
 }
 
 // LoadRegisterPairImmediate implements LXI rp, data 16. Byte 3 of the instruction is moved into the high-order register (rh) of the
 // register pair rp. Byte 2 of the instruction is moved into the low-order register (rl) of the register pair rp.
 func (cpu *CPU) LoadRegisterPairImmediate(rp *memory.RegisterPair) {
-	rp.Low.Write8(cpu.Memory[cpu.ProgramCounter+1])
-	rp.High.Write8(cpu.Memory[cpu.ProgramCounter+2])
+	rp.Low.Write8(cpu.Memory.Read(cpu.ProgramCounter+1))
+	rp.High.Write8(cpu.Memory.Read(cpu.ProgramCounter+2))
 	cpu.ProgramCounter += 3
 }
 
@@ -95,8 +106,8 @@ func (cpu *CPU) LoadRegisterPairImmediate(rp *memory.RegisterPair) {
 // is specified in byte 2 and byte 3 of the instruction, is moved to register A.
 func (cpu *CPU) LoadAccumulatorDirect() {
 	var memoryAddress uint16
-	memoryAddress = (uint16(cpu.Memory[cpu.ProgramCounter+2]) << 8) | uint16(cpu.Memory[cpu.ProgramCounter+1])
-	cpu.A.Write8(cpu.Memory[memoryAddress])
+	memoryAddress = (uint16(cpu.Memory.Read(cpu.ProgramCounter+2)) << 8) | uint16(cpu.Memory.Read(cpu.ProgramCounter+1))
+	cpu.A.Write8(cpu.Memory.Read(memoryAddress))
 	cpu.ProgramCounter += 3
 }
 
@@ -104,8 +115,10 @@ func (cpu *CPU) LoadAccumulatorDirect() {
 // memory location whose address is specified in byte 2 and byte 3 of the instruction.
 func (cpu *CPU) StoreAccumulatorDirect() {
 	var memoryAddress uint16
-	memoryAddress = (uint16(cpu.Memory[cpu.ProgramCounter+2]) << 8) | uint16(cpu.Memory[cpu.ProgramCounter+1])
-	cpu.A.Read8(&cpu.Memory[memoryAddress])
+	memoryAddress = (uint16(cpu.Memory.Read(cpu.ProgramCounter+2)) << 8) | uint16(cpu.Memory.Read(cpu.ProgramCounter+1))
+	var data uint8
+	cpu.A.Read8(&data)
+	cpu.Memory.Write(memoryAddress, data)
 	cpu.ProgramCounter += 3
 }
 
@@ -114,9 +127,9 @@ func (cpu *CPU) StoreAccumulatorDirect() {
 // moved to register L. The content of the memory location at the succeeding address is moved to register H.
 func (cpu *CPU) LoadHandLDirect() {
 	var memoryAddress uint16
-	memoryAddress = (uint16(cpu.Memory[cpu.ProgramCounter+2]) << 8) | uint16(cpu.Memory[cpu.ProgramCounter+1])
-	cpu.L.Write8(cpu.Memory[memoryAddress])
-	cpu.H.Write8(cpu.Memory[memoryAddress+1])
+	memoryAddress = (uint16(cpu.Memory.Read(cpu.ProgramCounter+2)) << 8) | uint16(cpu.Memory.Read(cpu.ProgramCounter+1))
+	cpu.L.Write8(cpu.Memory.Read(memoryAddress))
+	cpu.H.Write8(cpu.Memory.Read(memoryAddress+1))
 	cpu.ProgramCounter += 3
 }
 
@@ -125,9 +138,13 @@ func (cpu *CPU) LoadHandLDirect() {
 // 3. The content of register H is moved to the succeeding memory location.
 func (cpu *CPU) StoreHandLDirect() {
 	var memoryAddress uint16
-	memoryAddress = (uint16(cpu.Memory[cpu.ProgramCounter+2]) << 8) | uint16(cpu.Memory[cpu.ProgramCounter+1])
-	cpu.L.Read8(&cpu.Memory[memoryAddress])
-	cpu.H.Read8(&cpu.Memory[memoryAddress+1])
+	memoryAddress = (uint16(cpu.Memory.Read(cpu.ProgramCounter+2)) << 8) | uint16(cpu.Memory.Read(cpu.ProgramCounter+1))
+	var l uint8
+	var h uint8
+	cpu.L.Read8(&l)
+	cpu.H.Read8(&h)
+	cpu.Memory.Write(memoryAddress, l)
+	cpu.Memory.Write(memoryAddress+1, h)
 	cpu.ProgramCounter += 3
 }
 
@@ -137,7 +154,7 @@ func (cpu *CPU) StoreHandLDirect() {
 func (cpu *CPU) LoadAccumulatorIndirect(rp *memory.RegisterPair) {
 	var memoryAddress uint16
 	rp.Read16(&memoryAddress)
-	cpu.A.Write8(cpu.Memory[memoryAddress])
+	cpu.A.Write8(cpu.Memory.Read(memoryAddress))
 	cpu.ProgramCounter += 1
 }
 
@@ -146,7 +163,10 @@ func (cpu *CPU) LoadAccumulatorIndirect(rp *memory.RegisterPair) {
 func (cpu *CPU) StoreAccumulatorIndirect(rp *memory.RegisterPair) {
 	var memoryAddress uint16
 	rp.Read16(&memoryAddress)
-	cpu.A.Read8(&cpu.Memory[memoryAddress])
+
+	var data uint8
+	cpu.A.Read8(&data)
+	cpu.Memory.Write(memoryAddress, data)
 	cpu.ProgramCounter += 1
 }
 
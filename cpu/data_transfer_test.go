@@ -10,7 +10,7 @@ func TestCPU_Input(t *testing.T) {
 	var data uint8 = 0xAB
 	cpu := makeCPU(0, []uint8{uint8(IN), port, 0, 0, 0}, 5)
 	cpu.AddressBus = *memory.NewRegisterPair(0, 0)
-	cpu.DataBus = *memory.NewRegister(data)
+	cpu.AttachDevice(port, &testDevice{in: data})
 
 	cpu.Input()
 
@@ -35,6 +35,8 @@ func TestCPU_Output(t *testing.T) {
 	cpu.AddressBus = *memory.NewRegisterPair(0, 0)
 	cpu.DataBus = *memory.NewRegister(data)
 	cpu.A.Write8(data)
+	device := &testDevice{}
+	cpu.AttachDevice(port, device)
 
 	cpu.Output()
 
@@ -44,6 +46,10 @@ func TestCPU_Output(t *testing.T) {
 		t.Errorf("Expected data bus to contain %X but contained %X", data, dataBus)
 	}
 
+	if device.out != data || device.outPort != port {
+		t.Errorf("Expected device to receive %X on port %X but received %X on port %X", data, port, device.out, device.outPort)
+	}
+
 	expectedAddressBus := uint16(port)<<8 | uint16(port)
 	var addressBus uint16
 	cpu.AddressBus.Read16(&addressBus)
@@ -107,14 +113,14 @@ func TestCPU_MoveToMemory(t *testing.T) {
 	cpu.MoveToMemory(register)
 
 	// Confirm byte 2 of memory now hold's register's value
-	if cpu.Memory[0x0002] != data {
-		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory[2])
+	if cpu.Memory.Read(0x0002) != data {
+		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory.Read(2))
 	}
 }
 
 func TestCPU_MoveImmediate(t *testing.T) {
 	cpu := &CPU{
-		Memory:         []uint8{0, 0xAB},
+		Memory:         memory.RAM{0, 0xAB},
 		ProgramCounter: 0,
 	}
 
@@ -140,14 +146,14 @@ func TestCPU_MoveToMemoryImmediate(t *testing.T) {
 	cpu.MoveToMemoryImmediate()
 
 	// Confirm value in register was moved to memory location 0x0002
-	if cpu.Memory[2] != data {
-		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory[2])
+	if cpu.Memory.Read(2) != data {
+		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory.Read(2))
 	}
 }
 
 func TestCPU_LoadRegisterPairImmediate(t *testing.T) {
 	cpu := &CPU{
-		Memory:         []uint8{0, 0xAB, 0xCD},
+		Memory:         memory.RAM{0, 0xAB, 0xCD},
 		ProgramCounter: 0,
 	}
 	registerPair := memory.NewRegisterPair(0x00, 0x00)
@@ -182,7 +188,7 @@ func TestCPU_LoadAccumulatorDirect(t *testing.T) {
 	var registerAData uint8
 	cpu.A.Read8(&registerAData)
 	if registerAData != 0xAB {
-		t.Errorf("Expected accumulator (register A) to contain %X but contained %X", cpu.Memory[2], registerAData)
+		t.Errorf("Expected accumulator (register A) to contain %X but contained %X", cpu.Memory.Read(2), registerAData)
 	}
 }
 
@@ -197,8 +203,8 @@ func TestCPU_LoadAccumulatorIndirect(t *testing.T) {
 	// Confirm that accumulator (register A) contains data from memory location 0x0002
 	var registerAData uint8
 	cpu.A.Read8(&registerAData)
-	if registerAData != cpu.Memory[2] {
-		t.Errorf("Expected accumulator (register A) to contain %X but contained %X", cpu.Memory[2], registerAData)
+	if registerAData != cpu.Memory.Read(2) {
+		t.Errorf("Expected accumulator (register A) to contain %X but contained %X", cpu.Memory.Read(2), registerAData)
 	}
 }
 
@@ -210,8 +216,8 @@ func TestCPU_StoreAccumulatorDirect(t *testing.T) {
 	cpu.StoreAccumulatorDirect()
 
 	// Confirm that memory location 0x0002 holds 0xAB
-	if cpu.Memory[2] != data {
-		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory[2])
+	if cpu.Memory.Read(2) != data {
+		t.Errorf("Expected memory location 0x0002 to contain %X but contained %X", data, cpu.Memory.Read(2))
 	}
 }
 
@@ -225,8 +231,8 @@ func TestCPU_StoreAccumulatorIndirect(t *testing.T) {
 	cpu.StoreAccumulatorIndirect(sourceRegisterPair)
 
 	// Confirm that memory location 0x0002 holds 0xAB
-	if cpu.Memory[2] != data {
-		t.Errorf("Expected memory location 0x0002 to contain %X but contains %X", data, cpu.Memory[2])
+	if cpu.Memory.Read(2) != data {
+		t.Errorf("Expected memory location 0x0002 to contain %X but contains %X", data, cpu.Memory.Read(2))
 	}
 }
 
@@ -235,7 +241,7 @@ func TestCPU_LoadHandLDirect(t *testing.T) {
 	var expectedHData uint8 = 0xCD
 
 	cpu := &CPU{
-		Memory:         []uint8{0, 0x03, 0x00, expectedLData, expectedHData},
+		Memory:         memory.RAM{0, 0x03, 0x00, expectedLData, expectedHData},
 		H:              memory.NewRegister(0x00),
 		L:              memory.NewRegister(0x00),
 		ProgramCounter: 0,
@@ -261,18 +267,18 @@ func TestCPU_StoreHandLDirect(t *testing.T) {
 	var expectedMemoryHigh uint8 = 0xCD
 
 	cpu := &CPU{
-		Memory: []uint8{0, 0x03, 0x00, 0, 0},
+		Memory: memory.RAM{0, 0x03, 0x00, 0, 0},
 		H:      memory.NewRegister(expectedMemoryHigh),
 		L:      memory.NewRegister(expectedMemoryLow),
 	}
 
 	cpu.StoreHandLDirect()
 
-	if expectedMemoryLow != cpu.Memory[3] {
-		t.Errorf("Expected %X but got %X", expectedMemoryLow, cpu.Memory[0])
+	if expectedMemoryLow != cpu.Memory.Read(3) {
+		t.Errorf("Expected %X but got %X", expectedMemoryLow, cpu.Memory.Read(0))
 	}
-	if expectedMemoryHigh != cpu.Memory[4] {
-		t.Errorf("Expected %X but got %X", expectedMemoryHigh, cpu.Memory[1])
+	if expectedMemoryHigh != cpu.Memory.Read(4) {
+		t.Errorf("Expected %X but got %X", expectedMemoryHigh, cpu.Memory.Read(1))
 	}
 }
 
@@ -304,3 +310,46 @@ func TestCPU_ExchangeHandLWithDAndE(t *testing.T) {
 		t.Errorf("Expected HL to be 0xCDDE and DE to be 0xABCD but HL was %X and DE was %X", hl, de)
 	}
 }
+
+func TestCPU_DirectAddressWraparound(t *testing.T) {
+	// SHLD 0xFFFF stores L at 0xFFFF and H at 0x0000; LHLD 0xFFFF reads them back
+	cpu := makeInterruptCPU([]uint8{uint8(SHLD), 0xFF, 0xFF, uint8(LHLD), 0xFF, 0xFF})
+	cpu.HL.Write16(0xABCD)
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.Memory.Read(0xFFFF) != 0xCD || cpu.Memory.Read(0x0000) != 0xAB {
+		t.Errorf("Expected 0xABCD to straddle 0xFFFF but found 0x%02X%02X", cpu.Memory.Read(0x0000), cpu.Memory.Read(0xFFFF))
+	}
+
+	cpu.HL.Write16(0)
+	cpu.StandardInstructionCycle()
+
+	var hl uint16
+	cpu.HL.Read16(&hl)
+	if hl != 0xABCD {
+		t.Errorf("Expected LHLD to read 0xABCD across 0xFFFF but got 0x%X", hl)
+	}
+}
+
+func TestCPU_MemoryReferenceBoundaries(t *testing.T) {
+	// MVI M and MOV A,M at HL = 0xFFFF, then INX H wraps HL to 0x0000
+	cpu := makeInterruptCPU([]uint8{uint8(MVIM), 0x5A, uint8(MOVAM), uint8(INXH), uint8(MOVMA)})
+	cpu.HL.Write16(0xFFFF)
+
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	var a uint8
+	cpu.A.Read8(&a)
+	if cpu.Memory.Read(0xFFFF) != 0x5A || a != 0x5A {
+		t.Errorf("Expected 0x5A at 0xFFFF and in A but found 0x%02X and 0x%02X", cpu.Memory.Read(0xFFFF), a)
+	}
+
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	if cpu.Memory.Read(0x0000) != 0x5A {
+		t.Errorf("Expected MOV M,A to store at 0x0000 after HL wrapped but found 0x%02X", cpu.Memory.Read(0x0000))
+	}
+}
@@ -0,0 +1,76 @@
+package cpu
+
+import "fmt"
+
+// RequestInterrupt raises the INTR line on behalf of an interrupting device. instruction holds the bytes the
+// device places on the data bus during the interrupt acknowledge: normally a single RST, or a 3-byte CALL. The
+// request remains pending until the CPU accepts it, which happens only while interrupts are enabled. A new request
+// replaces any request that has not yet been accepted.
+func (cpu *CPU) RequestInterrupt(instruction ...uint8) {
+	if len(instruction) == 0 {
+		return
+	}
+	cpu.interruptRequest = append([]uint8(nil), instruction...)
+}
+
+// CancelInterrupt lowers the INTR line, discarding any pending interrupt request.
+func (cpu *CPU) CancelInterrupt() {
+	cpu.interruptRequest = nil
+}
+
+// InterruptPending reports whether an interrupt request is waiting to be accepted.
+func (cpu *CPU) InterruptPending() bool {
+	return cpu.interruptRequest != nil
+}
+
+// interruptAcceptable reports whether a pending interrupt is accepted before the next instruction. Interrupts
+// are only accepted while INTE is set, and not until the instruction following EI has executed.
+func (cpu *CPU) interruptAcceptable() bool {
+	return cpu.interruptRequest != nil && cpu.InterruptsEnabled && !cpu.interruptDelay
+}
+
+// InterruptInstructionCycle acknowledges the pending interrupt. The interrupt system is disabled (INTE is cleared
+// and stays clear until the program executes EI), the CPU is released from the halted state, and the instruction
+// supplied by the interrupting device is executed. The ProgramCounter is not incremented while the instruction is
+// fetched, so an RST or CALL pushes the address of the instruction that would otherwise have executed next. It
+// returns the number of T-states consumed, or 0 if no interrupt was pending.
+func (cpu *CPU) InterruptInstructionCycle() int {
+	instruction := cpu.interruptRequest
+	if instruction == nil {
+		return 0
+	}
+
+	cpu.interruptRequest = nil
+	cpu.interruptDelay = false
+	cpu.InterruptsEnabled = false
+	cpu.Halted = false
+
+	cpu.DataBus.Write8(instruction[0])
+	opcode, ok := cpu.resolveOpCode(OpCode(instruction[0]))
+	if !ok {
+		return cpu.unimplemented(opcode)
+	}
+
+	switch {
+	case opcode == CALL:
+		if len(instruction) < 3 {
+			cpu.Raise(fmt.Errorf("interrupting device supplied CALL without a 16-bit address: % X", instruction))
+			return 0
+		}
+
+		cpu.pushReturnAddress(cpu.ProgramCounter)
+		cpu.ProgramCounter = uint16(instruction[2])<<8 | uint16(instruction[1])
+
+		cycles := int(instructionCycles[opcode])
+		cpu.Cycles += uint64(cycles)
+		return cycles
+	case len(instruction) == 1:
+		// Execute the instruction as if it had been fetched from the byte preceding the ProgramCounter so that
+		// the ProgramCounter is left unchanged by a non-branching instruction and RST pushes its current value.
+		cpu.ProgramCounter--
+		return cpu.exec(opcode)
+	default:
+		cpu.Raise(fmt.Errorf("unsupported multi-byte interrupt instruction: % X", instruction))
+		return 0
+	}
+}
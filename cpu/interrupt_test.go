@@ -0,0 +1,175 @@
+package cpu
+
+import "testing"
+
+func makeInterruptCPU(program []uint8) *CPU {
+	cpu := new(CPU)
+	cpu.Init()
+	cpu.InterruptsEnabled = true // as if the program had executed EI
+	cpu.SP.Write16(0x2000)
+	cpu.ProgramCounter = 0x0100
+	for i, b := range program {
+		cpu.Memory.Write(0x0100+uint16(i), b)
+	}
+	return cpu
+}
+
+func readStackTop(cpu *CPU) uint16 {
+	var sp uint16
+	cpu.SP.Read16(&sp)
+	return uint16(cpu.Memory.Read(sp+1))<<8 | uint16(cpu.Memory.Read(sp))
+}
+
+func TestCPU_InterruptAccepted(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP), uint8(NOP)})
+	cpu.StandardInstructionCycle()
+
+	cpu.RequestInterrupt(uint8(RST1))
+	if cycles := cpu.StandardInstructionCycle(); cycles != 11 {
+		t.Errorf("Expected interrupt RST to take 11 cycles but took %d", cycles)
+	}
+
+	if cpu.ProgramCounter != 0x08 {
+		t.Errorf("Expected PC to be 0x08 but was 0x%X", cpu.ProgramCounter)
+	}
+
+	if ret := readStackTop(cpu); ret != 0x0101 {
+		t.Errorf("Expected return address 0x0101 to be pushed but was 0x%X", ret)
+	}
+
+	if cpu.InterruptsEnabled {
+		t.Error("Expected INTE to be cleared on acknowledge but it was set")
+	}
+
+	if cpu.InterruptPending() {
+		t.Error("Expected interrupt request to be consumed but it is still pending")
+	}
+}
+
+func TestCPU_InterruptIgnoredWhileDisabled(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(DI), uint8(NOP), uint8(NOP)})
+	cpu.StandardInstructionCycle()
+
+	cpu.RequestInterrupt(uint8(RST1))
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x0102 {
+		t.Errorf("Expected interrupt to be ignored and PC to be 0x0102 but was 0x%X", cpu.ProgramCounter)
+	}
+
+	if !cpu.InterruptPending() {
+		t.Error("Expected interrupt request to remain pending but it was dropped")
+	}
+}
+
+func TestCPU_InterruptDelayedAfterEI(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(DI), uint8(EI), uint8(NOP), uint8(NOP)})
+	cpu.StandardInstructionCycle()
+	cpu.RequestInterrupt(uint8(RST7))
+
+	// EI itself executes, then the instruction following it, before the interrupt is accepted
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 0x0103 {
+		t.Fatalf("Expected the instruction after EI to execute first but PC was 0x%X", cpu.ProgramCounter)
+	}
+
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 0x38 {
+		t.Errorf("Expected interrupt to be accepted after the instruction following EI but PC was 0x%X", cpu.ProgramCounter)
+	}
+
+	if ret := readStackTop(cpu); ret != 0x0103 {
+		t.Errorf("Expected return address 0x0103 but was 0x%X", ret)
+	}
+}
+
+func TestCPU_InterruptRemainsDisabledUntilEI(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP)})
+	cpu.Memory.Write(0x08, uint8(NOP))
+	cpu.Memory.Write(0x09, uint8(NOP))
+
+	cpu.RequestInterrupt(uint8(RST1))
+	cpu.StandardInstructionCycle()
+
+	cpu.RequestInterrupt(uint8(RST2))
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x0A {
+		t.Errorf("Expected second interrupt to wait for EI but PC was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_InterruptResumesHalt(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(EI), uint8(HLT), uint8(NOP)})
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	if !cpu.Halted {
+		t.Fatal("Expected CPU to be halted but was not")
+	}
+
+	cpu.RequestInterrupt(uint8(RST1))
+	cpu.StandardInstructionCycle()
+
+	if cpu.Halted {
+		t.Error("Expected interrupt to release the CPU from the halted state but it did not")
+	}
+
+	if ret := readStackTop(cpu); ret != 0x0102 {
+		t.Errorf("Expected return address after HLT (0x0102) but was 0x%X", ret)
+	}
+}
+
+func TestCPU_InterruptDoesNotResumeHaltWhileDisabled(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(DI), uint8(HLT)})
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	cpu.RequestInterrupt(uint8(RST1))
+	cpu.StandardInstructionCycle()
+
+	if !cpu.Halted {
+		t.Error("Expected CPU to remain halted with interrupts disabled but it resumed")
+	}
+}
+
+func TestCPU_InterruptCall(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP)})
+
+	cpu.RequestInterrupt(uint8(CALL), 0x34, 0x12)
+	if cycles := cpu.StandardInstructionCycle(); cycles != 17 {
+		t.Errorf("Expected interrupt CALL to take 17 cycles but took %d", cycles)
+	}
+
+	if cpu.ProgramCounter != 0x1234 {
+		t.Errorf("Expected PC to be 0x1234 but was 0x%X", cpu.ProgramCounter)
+	}
+
+	if ret := readStackTop(cpu); ret != 0x0100 {
+		t.Errorf("Expected return address 0x0100 but was 0x%X", ret)
+	}
+}
+
+func TestCPU_InterruptSingleByteInstruction(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP)})
+
+	cpu.RequestInterrupt(uint8(NOP))
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x0100 {
+		t.Errorf("Expected interrupt NOP to leave PC at 0x0100 but was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_CancelInterrupt(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP)})
+	cpu.RequestInterrupt(uint8(RST1))
+	cpu.CancelInterrupt()
+
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 0x0101 {
+		t.Errorf("Expected cancelled interrupt to be ignored but PC was 0x%X", cpu.ProgramCounter)
+	}
+}
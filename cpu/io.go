@@ -0,0 +1,101 @@
+package cpu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// IODevice is a peripheral attached to one of the 8080's 256 I/O ports. In is called when the CPU executes
+// IN for the port and must return the byte to load into the accumulator. Out is called when the CPU executes
+// OUT for the port with the content of the accumulator.
+type IODevice interface {
+	In(port uint8) uint8
+	Out(port uint8, v uint8)
+}
+
+// UnmappedPortPolicy selects how IN and OUT behave when no IODevice is attached to the addressed port.
+type UnmappedPortPolicy int
+
+const (
+	// UnmappedPortFloat reads 0xFF (the value of a floating data bus) and ignores writes.
+	UnmappedPortFloat UnmappedPortPolicy = iota
+
+	// UnmappedPortLog behaves like UnmappedPortFloat but logs every access.
+	UnmappedPortLog
+
+	// UnmappedPortTrap stops the CPU with an UnmappedPortError before the instruction completes.
+	UnmappedPortTrap
+)
+
+// UnmappedPortError is the fault raised by an IN or OUT instruction addressing a port with no attached
+// IODevice while the UnmappedPortTrap policy is in effect.
+type UnmappedPortError struct {
+	Port    uint8
+	Output  bool
+	Address uint16
+}
+
+func (e *UnmappedPortError) Error() string {
+	instruction := "IN"
+	if e.Output {
+		instruction = "OUT"
+	}
+	return fmt.Sprintf("%s from unmapped port 0x%02X at address 0x%04X", instruction, e.Port, e.Address)
+}
+
+// AttachDevice connects device to the given port, replacing any device previously attached to it. A single
+// device may be attached to several ports.
+func (cpu *CPU) AttachDevice(port uint8, device IODevice) {
+	cpu.Ports[port] = device
+}
+
+// DetachDevice disconnects whatever device is attached to the given port.
+func (cpu *CPU) DetachDevice(port uint8) {
+	cpu.Ports[port] = nil
+}
+
+// Raise stops the CPU with the given fault. No further instructions are executed until the fault is cleared.
+func (cpu *CPU) Raise(err error) {
+	cpu.Fault = err
+}
+
+// readPort dispatches an IN to the device attached to port. It returns false if the instruction must not
+// complete because the CPU trapped on an unmapped port.
+func (cpu *CPU) readPort(port uint8, data *uint8) bool {
+	if device := cpu.Ports[port]; device != nil {
+		*data = device.In(port)
+		return true
+	}
+
+	switch cpu.UnmappedPorts {
+	case UnmappedPortLog:
+		log.Printf("IN from unmapped port 0x%02X at address 0x%04X", port, cpu.ProgramCounter)
+	case UnmappedPortTrap:
+		cpu.Raise(&UnmappedPortError{Port: port, Address: cpu.ProgramCounter})
+		return false
+	}
+
+	*data = memory.FloatingBus
+	return true
+}
+
+// writePort dispatches an OUT to the device attached to port. It returns false if the instruction must not
+// complete because the CPU trapped on an unmapped port.
+func (cpu *CPU) writePort(port uint8, data uint8) bool {
+	if device := cpu.Ports[port]; device != nil {
+		device.Out(port, data)
+		return true
+	}
+
+	switch cpu.UnmappedPorts {
+	case UnmappedPortLog:
+		log.Printf("OUT 0x%02X to unmapped port 0x%02X at address 0x%04X", data, port, cpu.ProgramCounter)
+	case UnmappedPortTrap:
+		cpu.Raise(&UnmappedPortError{Port: port, Output: true, Address: cpu.ProgramCounter})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+type testDevice struct {
+	in      uint8
+	inPort  uint8
+	out     uint8
+	outPort uint8
+}
+
+func (d *testDevice) In(port uint8) uint8 {
+	d.inPort = port
+	return d.in
+}
+
+func (d *testDevice) Out(port uint8, v uint8) {
+	d.outPort = port
+	d.out = v
+}
+
+func TestCPU_AttachDevice(t *testing.T) {
+	device := &testDevice{in: 0xAB}
+	cpu := makeCPU(0, []uint8{uint8(IN), 0x10, uint8(IN), 0x11}, 0)
+	cpu.AttachDevice(0x10, device)
+	cpu.AttachDevice(0x11, device)
+
+	cpu.Input()
+	if device.inPort != 0x10 {
+		t.Errorf("Expected device to be read on port 0x10 but was read on 0x%X", device.inPort)
+	}
+
+	cpu.Input()
+	if device.inPort != 0x11 {
+		t.Errorf("Expected device to be read on port 0x11 but was read on 0x%X", device.inPort)
+	}
+}
+
+func TestCPU_DetachDevice(t *testing.T) {
+	cpu := makeCPU(0, []uint8{uint8(IN), 0x10}, 0)
+	cpu.AttachDevice(0x10, &testDevice{in: 0xAB})
+	cpu.DetachDevice(0x10)
+
+	cpu.Input()
+
+	var a uint8
+	cpu.A.Read8(&a)
+	if a != 0xFF {
+		t.Errorf("Expected detached port to read 0xFF but read 0x%X", a)
+	}
+}
+
+func TestCPU_UnmappedPortFloat(t *testing.T) {
+	cpu := makeCPU(0, []uint8{uint8(IN), 0x10, uint8(OUT), 0x10}, 0)
+
+	cpu.Input()
+	cpu.Output()
+
+	var a uint8
+	cpu.A.Read8(&a)
+	if a != 0xFF {
+		t.Errorf("Expected unmapped port to read 0xFF but read 0x%X", a)
+	}
+
+	if cpu.ProgramCounter != 4 {
+		t.Errorf("Expected PC to be 4 but was %d", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_UnmappedPortTrap(t *testing.T) {
+	cpu := makeCPU(0, []uint8{uint8(NOP), uint8(OUT), 0x10}, 0)
+	cpu.ProgramCounter = 1
+	cpu.UnmappedPorts = UnmappedPortTrap
+
+	cpu.Output()
+
+	var portErr *UnmappedPortError
+	if !errors.As(cpu.Fault, &portErr) {
+		t.Fatalf("Expected an UnmappedPortError fault but got %v", cpu.Fault)
+	}
+
+	if portErr.Port != 0x10 || !portErr.Output || portErr.Address != 1 {
+		t.Errorf("Expected OUT fault on port 0x10 at 0x0001 but got %+v", *portErr)
+	}
+
+	if cpu.ProgramCounter != 1 {
+		t.Errorf("Expected PC to remain at the trapping instruction but was %d", cpu.ProgramCounter)
+	}
+
+	// A faulted CPU must not fetch further instructions
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 1 {
+		t.Errorf("Expected faulted CPU to stay at PC 1 but was %d", cpu.ProgramCounter)
+	}
+}
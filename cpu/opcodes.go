@@ -14,7 +14,7 @@ const (
 	DCRB
 	MVIB
 	RLC
-	_ // 0x08 *NOP
+	NOP08 // 0x08 undocumented *NOP
 	DADB
 	LDAXB
 	DCXB
@@ -24,7 +24,7 @@ const (
 	RRC
 
 	// 0x1_
-	_ // 0x10 *NOP
+	NOP10 // 0x10 undocumented *NOP
 	LXID
 	STAXD
 	INXD
@@ -32,7 +32,7 @@ const (
 	DCRD
 	MVID
 	RAL
-	_ // 0x18 *NOP
+	NOP18 // 0x18 undocumented *NOP
 	DADD
 	LDAXD
 	DCXD
@@ -42,7 +42,7 @@ const (
 	RAR
 
 	// 0x2_
-	_ // 0x20 *NOP
+	NOP20 // 0x20 undocumented *NOP
 	LXIH
 	SHLD
 	INXH
@@ -50,7 +50,7 @@ const (
 	DCRH
 	MVIH
 	DAA
-	_ // 0x28 *NOP
+	NOP28 // 0x28 undocumented *NOP
 	DADH
 	LHLD
 	DCXH
@@ -60,7 +60,7 @@ const (
 	CMA
 
 	// 0x3_
-	_ // 0x30 *NOP
+	NOP30 // 0x30 undocumented *NOP
 	LXISP
 	STA
 	INXSP
@@ -68,7 +68,7 @@ const (
 	DCRM
 	MVIM
 	STC
-	_ // 0x38 *NOP
+	NOP38 // 0x38 undocumented *NOP
 	DADSP
 	LDA
 	DCXSP
@@ -233,7 +233,7 @@ const (
 	RZ
 	RET
 	JZ
-	_ // 0xCB *JMP
+	JMPCB // 0xCB undocumented *JMP
 	CZ
 	CALL
 	ACI
@@ -249,11 +249,11 @@ const (
 	SUI
 	RST2
 	RC
-	_ // 0xD9 *RET
+	RETD9 // 0xD9 undocumented *RET
 	JC
 	IN
 	CC
-	_ // 0xDD *CALL
+	CALLDD // 0xDD undocumented *CALL
 	SBI
 	RST3
 
@@ -271,7 +271,7 @@ const (
 	JPE
 	XCHG
 	CPE
-	_ // 0xED *CALL
+	CALLED // 0xED undocumented *CALL
 	XRI
 	RST5
 
@@ -289,7 +289,7 @@ const (
 	JM
 	EI
 	CM
-	_ // 0xFD *CALL
+	CALLFD // 0xFD undocumented *CALL
 	CPI
 	RST7
 )
@@ -0,0 +1,99 @@
+package cpu_test
+
+import (
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu/cputest"
+)
+
+func TestProgram_DataTransfer(t *testing.T) {
+	cputest.Run(t, `
+	ORG	100H
+	MVI	B,12H
+	MOV	C,B
+	LXI	H,BUFFER
+	MOV	M,C
+	MVI	M,34H
+	LDA	BUFFER
+	LXI	D,BUFFER+1
+	STAX	D
+	XCHG
+	HLT
+BUFFER:	DS	2
+	END	100H`).
+		Register(cputest.A, 0x34).
+		Register(cputest.C, 0x12).
+		Pair(cputest.HL, 0x0113).
+		Pair(cputest.DE, 0x0112).
+		Memory(0x0112, 0x34, 0x34).
+		Cycles(7 + 5 + 10 + 7 + 10 + 13 + 10 + 7 + 4 + 7)
+}
+
+func TestProgram_Stack(t *testing.T) {
+	cputest.Run(t, `
+	LXI	SP,2000H
+	LXI	B,1234H
+	PUSH	B
+	CALL	SWAP
+	POP	D
+	HLT
+SWAP:	XTHL
+	XTHL
+	MOV	A,L
+	RET`).
+		Pair(cputest.SP, 0x2000).
+		Pair(cputest.DE, 0x1234).
+		Word(0x1FFE, 0x1234).
+		Word(0x1FFC, 0x000A)
+}
+
+func TestProgram_ConditionalCallCycles(t *testing.T) {
+	cputest.Run(t, `
+	LXI	SP,2000H
+	XRA	A
+	CNZ	ROUTINE
+	CZ	ROUTINE
+	HLT
+ROUTINE:	RNZ
+	RET`).
+		Flags(cputest.Zero | cputest.Parity).
+		Cycles(10 + 4 + 11 + 17 + 5 + 10 + 7)
+}
+
+func TestProgram_Loop(t *testing.T) {
+	cputest.Run(t, `
+	MVI	B,10
+	XRA	A
+LOOP:	ADD	B
+	DCR	B
+	JNZ	LOOP
+	HLT`).
+		Register(cputest.A, 55).
+		Register(cputest.B, 0).
+		Set(cputest.Zero).
+		Instructions(2 + 3*10 + 1)
+}
+
+func TestProgram_AndImmediateAuxiliaryCarry(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        uint8
+		operand  string
+		expected uint8
+		flags    cputest.Flag
+	}{
+		{"bit 3 of the accumulator", 0x08, "01H", 0x00, cputest.Zero | cputest.Parity | cputest.AuxiliaryCarry},
+		{"bit 3 of the operand", 0x31, "0FH", 0x01, cputest.AuxiliaryCarry},
+		{"bit 3 of neither", 0x31, "07H", 0x01, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cputest.Assemble(t, "\tANI\t"+tt.operand+"\n\tHLT").
+				SetRegister(cputest.A, tt.a).
+				SetFlags(cputest.Carry | cputest.AuxiliaryCarry).
+				Run().
+				Register(cputest.A, tt.expected).
+				Flags(tt.flags)
+		})
+	}
+}
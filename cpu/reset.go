@@ -0,0 +1,78 @@
+package cpu
+
+import (
+	"math/rand"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// PowerOnPolicy selects the contents of the registers and memory when power is applied. A real 8080 powers up with
+// undefined register contents and whatever the RAM chips happen to hold; filling them with a pattern or random data
+// exposes guest software that reads state it never initialised.
+type PowerOnPolicy int
+
+const (
+	// PowerOnZeroed clears every register, flag and memory location.
+	PowerOnZeroed PowerOnPolicy = iota
+
+	// PowerOnPattern fills every register, flag and memory location with PowerOnState.Pattern.
+	PowerOnPattern
+
+	// PowerOnRandom fills every register, flag and memory location with pseudo-random data generated from
+	// PowerOnState.Seed, so that a run can be reproduced.
+	PowerOnRandom
+)
+
+// PowerOnState describes the state of the CPU and memory when power is applied.
+type PowerOnState struct {
+	Policy  PowerOnPolicy
+	Pattern uint8 // Fill byte for PowerOnPattern
+	Seed    int64 // Random seed for PowerOnRandom
+}
+
+// PowerOn applies power to an initialized CPU. The registers, flags and every byte of the address space are filled
+// according to state, and the CPU is then reset. Cycles and Instructions are cleared. A bus implementing
+// memory.Filler has only its RAM filled.
+func (cpu *CPU) PowerOn(state PowerOnState) {
+	var fill func() uint8
+	switch state.Policy {
+	case PowerOnPattern:
+		fill = func() uint8 { return state.Pattern }
+	case PowerOnRandom:
+		random := rand.New(rand.NewSource(state.Seed))
+		fill = func() uint8 { return uint8(random.Intn(0x100)) }
+	default:
+		fill = func() uint8 { return 0 }
+	}
+
+	cpu.A.Write8(fill())
+	cpu.ALU.ApplyStatusWord(fill())
+	for _, r := range []*memory.Register{cpu.B, cpu.C, cpu.D, cpu.E, cpu.H, cpu.L, cpu.W, cpu.Z} {
+		r.Write8(fill())
+	}
+	cpu.SP.Write16(uint16(fill())<<8 | uint16(fill()))
+
+	write := cpu.Memory.Write
+	if filler, ok := cpu.Memory.(memory.Filler); ok {
+		write = filler.Fill
+	}
+	for addr := 0; addr < memory.AddressSpaceSize; addr++ {
+		write(uint16(addr), fill())
+	}
+
+	cpu.Cycles = 0
+	cpu.Instructions = 0
+	cpu.Reset()
+}
+
+// Reset mirrors the 8080's RESET input. The ProgramCounter is cleared, the interrupt system is disabled (INTE is
+// cleared) and a halted CPU is released, as is a CPU stopped by a Fault. The other registers, the flags and memory
+// are not affected. An interrupt request that has not been accepted remains pending, since the interrupting device
+// still holds INTR.
+func (cpu *CPU) Reset() {
+	cpu.ProgramCounter = 0
+	cpu.InterruptsEnabled = false
+	cpu.interruptDelay = false
+	cpu.Halted = false
+	cpu.Fault = nil
+}
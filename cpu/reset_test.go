@@ -0,0 +1,138 @@
+package cpu
+
+import "testing"
+
+func TestCPU_InitResets(t *testing.T) {
+	cpu := &CPU{ProgramCounter: 0x1234, InterruptsEnabled: true, Halted: true}
+	cpu.Init()
+
+	if cpu.ProgramCounter != 0 || cpu.InterruptsEnabled || cpu.Halted {
+		t.Errorf("Expected PC 0 with interrupts disabled and not halted but got PC 0x%X, INTE %v, halted %v",
+			cpu.ProgramCounter, cpu.InterruptsEnabled, cpu.Halted)
+	}
+}
+
+func TestCPU_Reset(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(HLT)})
+	cpu.BC.Write16(0xABCD)
+	cpu.Memory.Write(0x2000, 0x5A)
+	cpu.StandardInstructionCycle()
+	cpu.RequestInterrupt(uint8(RST1))
+
+	cpu.Reset()
+
+	if cpu.ProgramCounter != 0 {
+		t.Errorf("Expected PC to be 0 but was 0x%X", cpu.ProgramCounter)
+	}
+	if cpu.InterruptsEnabled {
+		t.Error("Expected RESET to disable interrupts")
+	}
+	if cpu.Halted {
+		t.Error("Expected RESET to release the halted CPU")
+	}
+	if !cpu.InterruptPending() {
+		t.Error("Expected the interrupt request to remain pending")
+	}
+
+	var bc uint16
+	cpu.BC.Read16(&bc)
+	if bc != 0xABCD || cpu.Memory.Read(0x2000) != 0x5A {
+		t.Errorf("Expected registers and memory to be unaffected but BC was 0x%X and memory 0x%X", bc, cpu.Memory.Read(0x2000))
+	}
+
+	// The pending interrupt is not accepted until the program enables interrupts
+	cpu.Memory.Write(0x0000, uint8(NOP))
+	cpu.StandardInstructionCycle()
+	if cpu.ProgramCounter != 0x0001 {
+		t.Errorf("Expected execution to restart at 0x0000 but PC was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_ResetClearsFault(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP08)})
+	cpu.StrictOpcodes = true
+	cpu.StandardInstructionCycle()
+
+	cpu.Reset()
+
+	if cpu.Fault != nil {
+		t.Errorf("Expected RESET to clear the fault but got %v", cpu.Fault)
+	}
+}
+
+func TestCPU_PowerOnPattern(t *testing.T) {
+	cpu := new(CPU)
+	cpu.Init()
+	cpu.ProgramCounter = 0x1234
+	cpu.InterruptsEnabled = true
+	cpu.Cycles = 100
+
+	cpu.PowerOn(PowerOnState{Policy: PowerOnPattern, Pattern: 0xE5})
+
+	var a uint8
+	cpu.A.Read8(&a)
+	var bc, de, hl, sp uint16
+	cpu.BC.Read16(&bc)
+	cpu.DE.Read16(&de)
+	cpu.HL.Read16(&hl)
+	cpu.SP.Read16(&sp)
+	if a != 0xE5 || bc != 0xE5E5 || de != 0xE5E5 || hl != 0xE5E5 || sp != 0xE5E5 {
+		t.Errorf("Expected registers filled with 0xE5 but got A=0x%X BC=0x%X DE=0x%X HL=0x%X SP=0x%X", a, bc, de, hl, sp)
+	}
+
+	if status := cpu.ALU.CreateStatusWord(); status != 0xC7 {
+		t.Errorf("Expected status word 0xC7 but got 0x%X", status)
+	}
+
+	for _, addr := range []uint16{0x0000, 0x8000, 0xFFFF} {
+		if v := cpu.Memory.Read(addr); v != 0xE5 {
+			t.Errorf("Expected memory at 0x%04X to be 0xE5 but was 0x%X", addr, v)
+		}
+	}
+
+	if cpu.ProgramCounter != 0 || cpu.InterruptsEnabled || cpu.Cycles != 0 {
+		t.Errorf("Expected PowerOn to reset the CPU but PC was 0x%X, INTE %v, cycles %d",
+			cpu.ProgramCounter, cpu.InterruptsEnabled, cpu.Cycles)
+	}
+}
+
+func TestCPU_PowerOnZeroed(t *testing.T) {
+	cpu := new(CPU)
+	cpu.Init()
+	cpu.HL.Write16(0xFFFF)
+	cpu.Memory.Write(0x4000, 0xFF)
+	cpu.ALU.SetCarry()
+
+	cpu.PowerOn(PowerOnState{})
+
+	var hl uint16
+	cpu.HL.Read16(&hl)
+	if hl != 0 || cpu.Memory.Read(0x4000) != 0 || cpu.ALU.IsCarry() {
+		t.Errorf("Expected registers, flags and memory to be cleared")
+	}
+}
+
+func TestCPU_PowerOnRandom(t *testing.T) {
+	snapshot := func(seed int64) []uint8 {
+		cpu := new(CPU)
+		cpu.Init()
+		cpu.PowerOn(PowerOnState{Policy: PowerOnRandom, Seed: seed})
+
+		var hl, sp uint16
+		cpu.HL.Read16(&hl)
+		cpu.SP.Read16(&sp)
+		state := []uint8{uint8(hl), uint8(hl >> 8), uint8(sp), uint8(sp >> 8)}
+		for addr := 0; addr < 0x100; addr++ {
+			state = append(state, cpu.Memory.Read(uint16(addr)))
+		}
+		return state
+	}
+
+	first, second, other := snapshot(42), snapshot(42), snapshot(43)
+	if string(first) != string(second) {
+		t.Error("Expected the same seed to produce the same power-on state")
+	}
+	if string(first) == string(other) {
+		t.Error("Expected different seeds to produce different power-on states")
+	}
+}
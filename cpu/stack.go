@@ -2,8 +2,6 @@ package cpu
 
 import (
 	"github.com/cbush06/intel8080emulator/memory"
-	"log"
-	"strings"
 )
 
 // Call implements the CALL addr instruction. The high-order eight bits of the next instruction address
@@ -13,42 +11,25 @@ import (
 // is transferred to the instruction whose address is specified in byte 3 and byte 2 of the current
 // instruction.
 func (cpu *CPU) Call() {
-	var stackPointer uint16
-
 	nextInstruction := cpu.ProgramCounter + 3 // Jump ahead to whatever comes after the 3-byte CALL instruction
-	cpu.SP.Read16(&stackPointer)
-
-	nextHigh := uint8((nextInstruction & 0xFF00) >> 8)
-	nextLow := uint8(nextInstruction & 0xFF)
-
-	cpu.Memory[stackPointer-1] = nextHigh
-	cpu.Memory[stackPointer-2] = nextLow
-
-	cpu.SP.Write16(stackPointer - 2)
+	cpu.pushReturnAddress(nextInstruction)
 
 	cpu.ProgramCounter = cpu.getJumpAddress()
 }
 
-func (cpu *CPU) printDiagMessage() {
-	var cReg uint8
-	cpu.C.Read8(&cReg)
-
-	switch cReg {
-	case 9:
-		var messageAddr uint16
-		cpu.DE.Read16(&messageAddr)
+// pushReturnAddress pushes the address of the next instruction onto the stack as CALL and RST do. The
+// high-order byte is written to SP-1, the low-order byte to SP-2 and SP is decremented by 2.
+func (cpu *CPU) pushReturnAddress(nextInstruction uint16) {
+	var stackPointer uint16
+	cpu.SP.Read16(&stackPointer)
 
-		messageAddr += 3 // skip some prefix?
+	nextHigh := uint8((nextInstruction & 0xFF00) >> 8)
+	nextLow := uint8(nextInstruction & 0xFF)
 
-		builder := strings.Builder{}
-		for ; cpu.Memory[messageAddr] != '$'; messageAddr++ {
-			builder.WriteByte(cpu.Memory[messageAddr])
-		}
+	cpu.Memory.Write(stackPointer-1, nextHigh)
+	cpu.Memory.Write(stackPointer-2, nextLow)
 
-		log.Print(builder.String())
-	default:
-		log.Print("Print routine called")
-	}
+	cpu.SP.Write16(stackPointer - 2)
 }
 
 // Restart implements the RST n instruction. The high-order eight bits of the next instruction address
@@ -57,18 +38,8 @@ func (cpu *CPU) printDiagMessage() {
 // address is two less than the content of register SP. The content of register SP is decremented by two.
 // Control is transferred to the instruction whose address is eight times the content of NNN.
 func (cpu *CPU) Restart(opcode OpCode) {
-	var stackPointer uint16
-
 	nextInstruction := cpu.ProgramCounter + 1 // Jump ahead to whatever comes after the 1-byte RST instruction
-	cpu.SP.Read16(&stackPointer)
-
-	nextHigh := uint8((nextInstruction & 0xFF00) >> 8)
-	nextLow := uint8(nextInstruction & 0xFF)
-
-	cpu.Memory[stackPointer-1] = nextHigh
-	cpu.Memory[stackPointer-2] = nextLow
-
-	cpu.SP.Write16(stackPointer - 2)
+	cpu.pushReturnAddress(nextInstruction)
 
 	// Transfer control to Interrupt Handler by masking all but bits 4, 5, and 6
 	// and multiplying their value by 8
@@ -84,8 +55,8 @@ func (cpu *CPU) Return() {
 	var newProgramCounter uint16
 
 	cpu.SP.Read16(&stackPointer)
-	newProgramCounter |= uint16(cpu.Memory[stackPointer])
-	newProgramCounter |= uint16(cpu.Memory[stackPointer+1]) << 8
+	newProgramCounter |= uint16(cpu.Memory.Read(stackPointer))
+	newProgramCounter |= uint16(cpu.Memory.Read(stackPointer+1)) << 8
 
 	cpu.SP.Write16(stackPointer + 2)
 
@@ -104,8 +75,12 @@ func (cpu *CPU) Push(rp *memory.RegisterPair) {
 
 	var stackPointer uint16
 	cpu.SP.Read16(&stackPointer)
-	rp.ReadHigh(&cpu.Memory[stackPointer-1])
-	rp.ReadLow(&cpu.Memory[stackPointer-2])
+	var high uint8
+	var low uint8
+	rp.ReadHigh(&high)
+	rp.ReadLow(&low)
+	cpu.Memory.Write(stackPointer-1, high)
+	cpu.Memory.Write(stackPointer-2, low)
 	cpu.SP.Write16(stackPointer - 2)
 	cpu.ProgramCounter += 1
 }
@@ -117,8 +92,10 @@ func (cpu *CPU) Push(rp *memory.RegisterPair) {
 func (cpu *CPU) PushProcessorStatusWord() {
 	var stackPointer uint16
 	cpu.SP.Read16(&stackPointer)
-	cpu.A.Read8(&cpu.Memory[stackPointer-1])
-	cpu.Memory[stackPointer-2] = cpu.ALU.CreateStatusWord()
+	var a uint8
+	cpu.A.Read8(&a)
+	cpu.Memory.Write(stackPointer-1, a)
+	cpu.Memory.Write(stackPointer-2, cpu.ALU.CreateStatusWord())
 	cpu.SP.Write16(stackPointer - 2)
 	cpu.ProgramCounter += 1
 }
@@ -131,8 +108,8 @@ func (cpu *CPU) PushProcessorStatusWord() {
 func (cpu *CPU) Pop(rp *memory.RegisterPair) {
 	var stackPointer uint16
 	cpu.SP.Read16(&stackPointer)
-	rp.WriteLow(cpu.Memory[stackPointer])
-	rp.WriteHigh(cpu.Memory[stackPointer+1])
+	rp.WriteLow(cpu.Memory.Read(stackPointer))
+	rp.WriteHigh(cpu.Memory.Read(stackPointer+1))
 	cpu.SP.Write16(stackPointer + 2)
 	cpu.ProgramCounter += 1
 }
@@ -144,8 +121,8 @@ func (cpu *CPU) Pop(rp *memory.RegisterPair) {
 func (cpu *CPU) PopProcessorStatusWord() {
 	var stackPointer uint16
 	cpu.SP.Read16(&stackPointer)
-	cpu.ALU.ApplyStatusWord(cpu.Memory[stackPointer])
-	cpu.ALU.GetA().Write8(cpu.Memory[stackPointer+1])
+	cpu.ALU.ApplyStatusWord(cpu.Memory.Read(stackPointer))
+	cpu.ALU.GetA().Write8(cpu.Memory.Read(stackPointer+1))
 	cpu.SP.Write16(stackPointer + 2)
 	cpu.ProgramCounter += 1
 }
@@ -160,8 +137,8 @@ func (cpu *CPU) ExchangeStackTopWithHandL() {
 	var stackPointer uint16
 	cpu.SP.Read16(&stackPointer)
 
-	stackL := cpu.Memory[stackPointer]
-	stackH := cpu.Memory[stackPointer+1]
+	stackL := cpu.Memory.Read(stackPointer)
+	stackH := cpu.Memory.Read(stackPointer+1)
 
 	var h uint8
 	var l uint8
@@ -169,8 +146,8 @@ func (cpu *CPU) ExchangeStackTopWithHandL() {
 	cpu.H.Read8(&h)
 	cpu.L.Read8(&l)
 
-	cpu.Memory[stackPointer] = l
-	cpu.Memory[stackPointer+1] = h
+	cpu.Memory.Write(stackPointer, l)
+	cpu.Memory.Write(stackPointer+1, h)
 
 	cpu.H.Write8(stackH)
 	cpu.L.Write8(stackL)
@@ -178,10 +155,11 @@ func (cpu *CPU) ExchangeStackTopWithHandL() {
 	cpu.ProgramCounter += 1
 }
 
-// EnableInterrupts implements the EI instruction. The interrupt system is enabled immediately following the
-// execution of the EI instruction.
+// EnableInterrupts implements the EI instruction. The interrupt system is enabled following the execution of
+// the next instruction.
 func (cpu *CPU) EnableInterrupts() {
 	cpu.InterruptsEnabled = true
+	cpu.interruptDelay = true
 	cpu.ProgramCounter += 1
 }
 
@@ -192,6 +170,13 @@ func (cpu *CPU) DisableInterrupts() {
 	cpu.ProgramCounter += 1
 }
 
+// Halt implements the HLT instruction. The processor is stopped. The registers and flags are unaffected. The
+// ProgramCounter is advanced past the HLT so that an interrupt resumes execution at the following instruction.
+func (cpu *CPU) Halt() {
+	cpu.Halted = true
+	cpu.ProgramCounter += 1
+}
+
 // MoveHLToSP implements the SPHL instruction. (SP) <- (H) (L). The contents of registers Hand L (16 bits) are moved
 // to register SP.
 func (cpu *CPU) MoveHLToSP() {
@@ -1,6 +1,7 @@
 package cpu
 
 import (
+	"github.com/cbush06/intel8080emulator/alu"
 	"github.com/cbush06/intel8080emulator/memory"
 	"testing"
 )
@@ -15,11 +16,11 @@ func TestCPU_Call(t *testing.T) {
 	cpu.Call()
 
 	// Verify next instruction ADDRESS is stored in SP-1 and SP-2
-	if cpu.Memory[4] != uint8(0x00) {
-		t.Errorf("Expected Memory[SP - 1] to be 0x0 but was 0x%X", cpu.Memory[4])
+	if cpu.Memory.Read(4) != uint8(0x00) {
+		t.Errorf("Expected Memory[SP - 1] to be 0x0 but was 0x%X", cpu.Memory.Read(4))
 	}
-	if cpu.Memory[3] != uint8(0x03) {
-		t.Errorf("Expected memory[SP - 2] to be 0x3 but was 0x%X", cpu.Memory[3])
+	if cpu.Memory.Read(3) != uint8(0x03) {
+		t.Errorf("Expected memory[SP - 2] to be 0x3 but was 0x%X", cpu.Memory.Read(3))
 	}
 
 	// Verify SP is decremented twice
@@ -67,11 +68,11 @@ func TestCPU_Restart(t *testing.T) {
 		cpu.Restart(opcode)
 
 		// Verify next instruction ADDRESS is stored in SP-1 and SP-2
-		if cpu.Memory[4] != uint8(0x00) {
-			t.Errorf("Expected Memory[SP - 1] to be 0x0 but was 0x%X", cpu.Memory[4])
+		if cpu.Memory.Read(4) != uint8(0x00) {
+			t.Errorf("Expected Memory[SP - 1] to be 0x0 but was 0x%X", cpu.Memory.Read(4))
 		}
-		if cpu.Memory[3] != uint8(0x01) {
-			t.Errorf("Expected memory[SP - 2] to be 0x1 but was 0x%X", cpu.Memory[3])
+		if cpu.Memory.Read(3) != uint8(0x01) {
+			t.Errorf("Expected memory[SP - 2] to be 0x1 but was 0x%X", cpu.Memory.Read(3))
 		}
 
 		// Verify SP is decremented twice
@@ -124,8 +125,8 @@ func TestCPU_ExchangeStackTopWithHandL(t *testing.T) {
 
 	cpu.ExchangeStackTopWithHandL()
 
-	if cpu.Memory[1] != 0xAB || cpu.Memory[2] != 0xCD {
-		t.Errorf("Expected new stack top to be 0xABCD but was 0x%X%X", cpu.Memory[1], cpu.Memory[2])
+	if cpu.Memory.Read(1) != 0xAB || cpu.Memory.Read(2) != 0xCD {
+		t.Errorf("Expected new stack top to be 0xABCD but was 0x%X%X", cpu.Memory.Read(1), cpu.Memory.Read(2))
 	}
 
 	var l uint8
@@ -157,6 +158,19 @@ func TestCPU_DisableInterrupts(t *testing.T) {
 	}
 }
 
+func TestCPU_Halt(t *testing.T) {
+	cpu := makeCPU(0, []uint8{uint8(HLT), 0}, 0)
+	cpu.Halt()
+
+	if !cpu.Halted {
+		t.Error("Expected CPU to be halted but was not")
+	}
+
+	if cpu.ProgramCounter != 1 {
+		t.Errorf("Expected PC to be 1 but was %d", cpu.ProgramCounter)
+	}
+}
+
 func TestCPU_MoveHLToSP(t *testing.T) {
 	var hl uint16
 	var sp uint16
@@ -174,3 +188,134 @@ func TestCPU_MoveHLToSP(t *testing.T) {
 		t.Errorf("Expected HL to be 0xCDDE and SP to be 0xABCD but HL was 0x%X and SP was 0x%X", hl, sp)
 	}
 }
+
+func TestCPU_ProcessorStatusWordRoundTrip(t *testing.T) {
+	for b := 0; b < 256; b++ {
+		cpu := makeInterruptCPU([]uint8{uint8(POPPSW), uint8(PUSHPSW)})
+		cpu.Memory.Write(0x2000, uint8(b))
+		cpu.Memory.Write(0x2001, 0xA5)
+
+		cpu.StandardInstructionCycle()
+
+		var a uint8
+		cpu.A.Read8(&a)
+		if a != 0xA5 {
+			t.Fatalf("Expected POP PSW to load A with 0xA5 but got 0x%X", a)
+		}
+
+		// Clear the popped word so PUSH PSW must regenerate it from the flags
+		cpu.Memory.Write(0x2000, 0)
+		cpu.Memory.Write(0x2001, 0)
+		cpu.StandardInstructionCycle()
+
+		expected := uint8(b)&alu.StatusWordFlags | 0x02
+		if status := cpu.Memory.Read(0x2000); status != expected {
+			t.Errorf("Expected status word 0x%02X to be pushed as 0x%02X but got 0x%02X", b, expected, status)
+		}
+		if pushedA := cpu.Memory.Read(0x2001); pushedA != 0xA5 {
+			t.Errorf("Expected PUSH PSW to store A as 0xA5 but got 0x%X", pushedA)
+		}
+
+		var sp uint16
+		cpu.SP.Read16(&sp)
+		if sp != 0x2000 {
+			t.Errorf("Expected SP to be 0x2000 but was 0x%X", sp)
+		}
+	}
+}
+
+func TestCPU_ProcessorStatusWordFlags(t *testing.T) {
+	// SUB A sets Z, P and AC and clears S and CY
+	cpu := makeInterruptCPU([]uint8{uint8(SUBA), uint8(PUSHPSW)})
+
+	cpu.StandardInstructionCycle()
+	cpu.StandardInstructionCycle()
+
+	if status := cpu.Memory.Read(0x1FFE); status != 0x56 {
+		t.Errorf("Expected status word 0x56 after SUB A but got 0x%02X", status)
+	}
+}
+
+func TestCPU_StackWraparound(t *testing.T) {
+	// SP = 0x0000 pushes to 0xFFFF and 0xFFFE
+	cpu := makeInterruptCPU([]uint8{uint8(PUSHB), uint8(POPD)})
+	cpu.SP.Write16(0x0000)
+	cpu.BC.Write16(0xABCD)
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.Memory.Read(0xFFFF) != 0xAB || cpu.Memory.Read(0xFFFE) != 0xCD {
+		t.Errorf("Expected 0xABCD to be pushed at 0xFFFE but found 0x%02X%02X", cpu.Memory.Read(0xFFFF), cpu.Memory.Read(0xFFFE))
+	}
+
+	var sp uint16
+	cpu.SP.Read16(&sp)
+	if sp != 0xFFFE {
+		t.Errorf("Expected SP to be 0xFFFE but was 0x%X", sp)
+	}
+
+	// SP = 0xFFFE pops back to 0x0000
+	cpu.StandardInstructionCycle()
+
+	var de uint16
+	cpu.DE.Read16(&de)
+	if de != 0xABCD {
+		t.Errorf("Expected POP to load 0xABCD but got 0x%X", de)
+	}
+
+	cpu.SP.Read16(&sp)
+	if sp != 0x0000 {
+		t.Errorf("Expected SP to wrap to 0x0000 but was 0x%X", sp)
+	}
+}
+
+func TestCPU_StackStraddlesWraparound(t *testing.T) {
+	// SP = 0x0001 makes CALL push the high byte to 0x0000 and the low byte to 0xFFFF
+	cpu := makeInterruptCPU([]uint8{uint8(CALL), 0x00, 0x02})
+	cpu.SP.Write16(0x0001)
+	cpu.Memory.Write(0x0200, uint8(XTHL))
+	cpu.Memory.Write(0x0201, uint8(RET))
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.Memory.Read(0x0000) != 0x01 || cpu.Memory.Read(0xFFFF) != 0x03 {
+		t.Errorf("Expected return address 0x0103 to straddle 0xFFFF but found 0x%02X%02X", cpu.Memory.Read(0x0000), cpu.Memory.Read(0xFFFF))
+	}
+
+	cpu.HL.Write16(0x0103)
+	cpu.StandardInstructionCycle()
+
+	var hl uint16
+	cpu.HL.Read16(&hl)
+	if hl != 0x0103 {
+		t.Errorf("Expected XTHL to read 0x0103 across 0xFFFF but got 0x%X", hl)
+	}
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x0103 {
+		t.Errorf("Expected RET to return to 0x0103 but PC was 0x%X", cpu.ProgramCounter)
+	}
+
+	var sp uint16
+	cpu.SP.Read16(&sp)
+	if sp != 0x0001 {
+		t.Errorf("Expected SP to wrap to 0x0001 but was 0x%X", sp)
+	}
+}
+
+func TestCPU_RestartWraparound(t *testing.T) {
+	cpu := makeInterruptCPU(nil)
+	cpu.ProgramCounter = 0xFFFF
+	cpu.Memory.Write(0xFFFF, uint8(RST1))
+
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x0008 {
+		t.Errorf("Expected PC to be 0x0008 but was 0x%X", cpu.ProgramCounter)
+	}
+
+	if ret := readStackTop(cpu); ret != 0x0000 {
+		t.Errorf("Expected RST at 0xFFFF to push return address 0x0000 but pushed 0x%X", ret)
+	}
+}
@@ -0,0 +1,28 @@
+package cpu
+
+// documentedOpCode returns the documented instruction that opcode behaves as on real 8080 silicon. The second
+// result is true if opcode is one of the undocumented aliases: 0x08, 0x10, 0x18, 0x20, 0x28, 0x30 and 0x38 execute
+// as NOP, 0xCB as JMP, 0xD9 as RET, and 0xDD, 0xED and 0xFD as CALL.
+func documentedOpCode(opcode OpCode) (OpCode, bool) {
+	switch opcode {
+	case NOP08, NOP10, NOP18, NOP20, NOP28, NOP30, NOP38:
+		return NOP, true
+	case JMPCB:
+		return JMP, true
+	case RETD9:
+		return RET, true
+	case CALLDD, CALLED, CALLFD:
+		return CALL, true
+	}
+	return opcode, false
+}
+
+// resolveOpCode maps an undocumented alias to its documented counterpart. It returns false if opcode is
+// undocumented and the CPU is in strict mode, in which case the opcode must be treated as unimplemented.
+func (cpu *CPU) resolveOpCode(opcode OpCode) (OpCode, bool) {
+	documented, undocumented := documentedOpCode(opcode)
+	if undocumented && cpu.StrictOpcodes {
+		return opcode, false
+	}
+	return documented, true
+}
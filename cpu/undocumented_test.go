@@ -0,0 +1,93 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCPU_UndocumentedNOP(t *testing.T) {
+	for _, opcode := range []OpCode{NOP08, NOP10, NOP18, NOP20, NOP28, NOP30, NOP38} {
+		cpu := makeInterruptCPU([]uint8{uint8(opcode)})
+
+		if cycles := cpu.StandardInstructionCycle(); cycles != 4 {
+			t.Errorf("Expected opcode 0x%02X to take 4 cycles but took %d", uint8(opcode), cycles)
+		}
+
+		if cpu.ProgramCounter != 0x0101 {
+			t.Errorf("Expected opcode 0x%02X to advance PC to 0x0101 but PC was 0x%X", uint8(opcode), cpu.ProgramCounter)
+		}
+	}
+}
+
+func TestCPU_UndocumentedJMP(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(JMPCB), 0x34, 0x12})
+
+	if cycles := cpu.StandardInstructionCycle(); cycles != 10 {
+		t.Errorf("Expected undocumented JMP to take 10 cycles but took %d", cycles)
+	}
+
+	if cpu.ProgramCounter != 0x1234 {
+		t.Errorf("Expected PC to be 0x1234 but was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_UndocumentedCALLAndRET(t *testing.T) {
+	for _, opcode := range []OpCode{CALLDD, CALLED, CALLFD} {
+		cpu := makeInterruptCPU([]uint8{uint8(opcode), 0x00, 0x02})
+		cpu.Memory.Write(0x0200, uint8(RETD9))
+
+		if cycles := cpu.StandardInstructionCycle(); cycles != 17 {
+			t.Errorf("Expected opcode 0x%02X to take 17 cycles but took %d", uint8(opcode), cycles)
+		}
+
+		if cpu.ProgramCounter != 0x0200 {
+			t.Fatalf("Expected opcode 0x%02X to call 0x0200 but PC was 0x%X", uint8(opcode), cpu.ProgramCounter)
+		}
+
+		if ret := readStackTop(cpu); ret != 0x0103 {
+			t.Errorf("Expected opcode 0x%02X to push return address 0x0103 but pushed 0x%X", uint8(opcode), ret)
+		}
+
+		if cycles := cpu.StandardInstructionCycle(); cycles != 10 {
+			t.Errorf("Expected undocumented RET to take 10 cycles but took %d", cycles)
+		}
+
+		if cpu.ProgramCounter != 0x0103 {
+			t.Errorf("Expected undocumented RET to return to 0x0103 but PC was 0x%X", cpu.ProgramCounter)
+		}
+	}
+}
+
+func TestCPU_UndocumentedStrict(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP), uint8(CALLFD), 0x00, 0x02})
+	cpu.StrictOpcodes = true
+
+	cpu.StandardInstructionCycle()
+	if cycles := cpu.StandardInstructionCycle(); cycles != 0 {
+		t.Errorf("Expected trapped opcode to consume no cycles but consumed %d", cycles)
+	}
+
+	var unimplemented *UnimplementedOpcodeError
+	if !errors.As(cpu.Fault, &unimplemented) {
+		t.Fatalf("Expected an UnimplementedOpcodeError fault but got %v", cpu.Fault)
+	}
+
+	if unimplemented.OpCode != CALLFD || unimplemented.Address != 0x0101 || !unimplemented.Undocumented {
+		t.Errorf("Expected fault for undocumented opcode 0xFD at 0x0101 but got %v", unimplemented)
+	}
+
+	if cpu.ProgramCounter != 0x0101 {
+		t.Errorf("Expected PC to remain at the trapped opcode but was 0x%X", cpu.ProgramCounter)
+	}
+}
+
+func TestCPU_UndocumentedInterruptCall(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP)})
+
+	cpu.RequestInterrupt(uint8(CALLDD), 0x34, 0x12)
+	cpu.StandardInstructionCycle()
+
+	if cpu.ProgramCounter != 0x1234 {
+		t.Errorf("Expected PC to be 0x1234 but was 0x%X", cpu.ProgramCounter)
+	}
+}
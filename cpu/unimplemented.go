@@ -0,0 +1,45 @@
+package cpu
+
+import "fmt"
+
+// UnimplementedOpcodeError is the fault raised when the CPU fetches an opcode it cannot execute. This includes the
+// undocumented opcode aliases while StrictOpcodes is set.
+type UnimplementedOpcodeError struct {
+	OpCode       OpCode
+	Address      uint16
+	Undocumented bool
+}
+
+func (e *UnimplementedOpcodeError) Error() string {
+	if e.Undocumented {
+		return fmt.Sprintf("undocumented opcode 0x%02X at address 0x%04X", uint8(e.OpCode), e.Address)
+	}
+	return fmt.Sprintf("unimplemented opcode 0x%02X at address 0x%04X", uint8(e.OpCode), e.Address)
+}
+
+// UnimplementedOpcodeHook is called when the CPU fetches an opcode it cannot execute. The hook may emulate the
+// instruction itself, in which case it must advance the ProgramCounter and return the number of T-states consumed
+// with a nil error. Returning an error (normally err itself) stops the CPU with that error as its Fault.
+type UnimplementedOpcodeHook func(cpu *CPU, err *UnimplementedOpcodeError) (int, error)
+
+// unimplemented handles an opcode the CPU cannot execute by passing it to the UnimplementedOpcode hook, or raising
+// an UnimplementedOpcodeError if no hook is set. The ProgramCounter is left on the offending opcode. It returns
+// the number of T-states consumed.
+func (cpu *CPU) unimplemented(opcode OpCode) int {
+	_, undocumented := documentedOpCode(opcode)
+	err := &UnimplementedOpcodeError{OpCode: opcode, Address: cpu.ProgramCounter, Undocumented: undocumented}
+
+	if cpu.UnimplementedOpcode == nil {
+		cpu.Raise(err)
+		return 0
+	}
+
+	cycles, hookErr := cpu.UnimplementedOpcode(cpu, err)
+	if hookErr != nil {
+		cpu.Raise(hookErr)
+		return 0
+	}
+
+	cpu.Cycles += uint64(cycles)
+	return cycles
+}
@@ -0,0 +1,62 @@
+package cpu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCPU_UnimplementedOpcodeHookEmulates(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(NOP38), uint8(NOP)})
+	cpu.StrictOpcodes = true
+
+	var trapped *UnimplementedOpcodeError
+	cpu.UnimplementedOpcode = func(c *CPU, err *UnimplementedOpcodeError) (int, error) {
+		trapped = err
+		c.ProgramCounter++
+		return 4, nil
+	}
+
+	if cycles := cpu.StandardInstructionCycle(); cycles != 4 {
+		t.Errorf("Expected the hook's 4 cycles to be returned but got %d", cycles)
+	}
+
+	if trapped == nil || trapped.OpCode != NOP38 || trapped.Address != 0x0100 {
+		t.Fatalf("Expected hook to be called for opcode 0x38 at 0x0100 but got %v", trapped)
+	}
+
+	if cpu.Fault != nil {
+		t.Errorf("Expected no fault after the hook emulated the opcode but got %v", cpu.Fault)
+	}
+
+	if cpu.ProgramCounter != 0x0101 || cpu.Cycles != 4 {
+		t.Errorf("Expected PC 0x0101 and 4 cycles but got PC 0x%X and %d cycles", cpu.ProgramCounter, cpu.Cycles)
+	}
+}
+
+func TestCPU_UnimplementedOpcodeHookFaults(t *testing.T) {
+	cpu := makeInterruptCPU([]uint8{uint8(JMPCB), 0x00, 0x00})
+	cpu.StrictOpcodes = true
+
+	cpu.UnimplementedOpcode = func(c *CPU, err *UnimplementedOpcodeError) (int, error) {
+		return 0, err
+	}
+
+	cpu.StandardInstructionCycle()
+
+	var unimplemented *UnimplementedOpcodeError
+	if !errors.As(cpu.Fault, &unimplemented) || unimplemented.OpCode != JMPCB {
+		t.Fatalf("Expected the hook's UnimplementedOpcodeError to fault the CPU but got %v", cpu.Fault)
+	}
+
+	// A faulted CPU executes nothing further
+	if cycles := cpu.StandardInstructionCycle(); cycles != 0 || cpu.ProgramCounter != 0x0100 {
+		t.Errorf("Expected faulted CPU to stay at 0x0100 but executed %d cycles to PC 0x%X", cycles, cpu.ProgramCounter)
+	}
+}
+
+func TestUnimplementedOpcodeError_Error(t *testing.T) {
+	err := &UnimplementedOpcodeError{OpCode: 0xCB, Address: 0x1234, Undocumented: true}
+	if msg := err.Error(); msg != "undocumented opcode 0xCB at address 0x1234" {
+		t.Errorf("Unexpected error message %q", msg)
+	}
+}
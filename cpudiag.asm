@@ -24,7 +24,7 @@
 ;
 ;
 	DB	'MICROCOSM ASSOCIATES 8080/8085 CPU DIAGNOSTIC'
-	DB	' VERSION 1.0  (C) 1980'
+	DB	' VERSION 1.0 (C) 1980'
 ;
 ;
 ;
@@ -79,7 +79,7 @@ BYTO3:	ADI	30H
 ;
 OKCPU:	DB	0CH,0DH,0AH,' CPU IS OPERATIONAL$'
 ;
-NGCPU:	DB	0CH,0DH,0AH,' CPU HAS FAILED!    ERROR EXIT=$'
+NGCPU:	DB	0CH,0DH,0AH,' CPU HAS FAILED! ERROR EXIT=$'
 ;
 ;
 ;
@@ -625,7 +625,7 @@ MOVI:	MVI	A,077H
 	CNZ	CPUER	;TEST "DAD" H
 	LXI	H,05555H
 	LXI	B,0FFFFH
-	DAD	B.  <!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!
+	DAD	B
 	MVI	A,055H
 	CNC	CPUER	;TEST "DAD" B
 	CMP	H
@@ -801,7 +801,7 @@ SAVSTK:	DS	2	;TEMPORARY STACK-POINTER STORAGE LOCATION
 ;
 ;
 ;
-STACK	EQU	TEMPP+256	;DE-BUG STACK POINTER STORAGE AREA
+STACK	EQU	$+256	;DE-BUG STACK POINTER STORAGE AREA
 ;
 ;
 ;
@@ -0,0 +1,188 @@
+// Package disasm decodes Intel 8080 machine code into structured instructions and formats them in Intel assembly
+// syntax.
+package disasm
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// OperandKind identifies what an Operand refers to.
+type OperandKind int
+
+const (
+	// Register is one of B, C, D, E, H, L, M or A. M is the memory location addressed by HL.
+	Register OperandKind = iota
+
+	// RegisterPair is B, D, H, SP or PSW.
+	RegisterPair
+
+	// Immediate8 is an 8-bit data byte.
+	Immediate8
+
+	// Immediate16 is a 16-bit data word.
+	Immediate16
+
+	// Address is a 16-bit memory address: a branch target or the operand of LDA, STA, LHLD or SHLD.
+	Address
+
+	// Port is an I/O port number.
+	Port
+
+	// Vector is the number (0 to 7) of a restart.
+	Vector
+)
+
+// Operand is an operand of an Instruction. Name holds the register or register pair; Value holds everything else.
+type Operand struct {
+	Kind  OperandKind
+	Name  string
+	Value uint16
+}
+
+// Flow describes how an instruction affects the flow of control.
+type Flow int
+
+const (
+	// Sequential instructions continue with the next instruction.
+	Sequential Flow = iota
+
+	// Jump transfers control to Target.
+	Jump
+
+	// ConditionalJump transfers control to Target or continues with the next instruction.
+	ConditionalJump
+
+	// Call calls Target and then continues with the next instruction.
+	Call
+
+	// ConditionalCall may call Target and then continues with the next instruction.
+	ConditionalCall
+
+	// Return returns to the caller.
+	Return
+
+	// ConditionalReturn may return to the caller, and otherwise continues with the next instruction.
+	ConditionalReturn
+
+	// IndirectJump transfers control to the address in HL (PCHL).
+	IndirectJump
+
+	// Halt stops the processor until an interrupt (HLT).
+	Halt
+)
+
+// Instruction is a decoded 8080 instruction.
+type Instruction struct {
+	Address      uint16
+	OpCode       cpu.OpCode
+	Mnemonic     string
+	Operands     []Operand
+	Length       int
+	Bytes        []uint8
+	Flow         Flow
+	Target       uint16 // Branch target of a jump, call or restart
+	Undocumented bool   // The opcode is an undocumented alias of Mnemonic
+}
+
+// HasTarget reports whether the instruction transfers control to Target.
+func (inst Instruction) HasTarget() bool {
+	switch inst.Flow {
+	case Jump, ConditionalJump, Call, ConditionalCall:
+		return true
+	}
+	return false
+}
+
+// Decode decodes the instruction at addr in mem. Operand bytes past 0xFFFF are read from 0x0000 onwards, as the CPU
+// would fetch them. Every byte decodes to some instruction, so Decode cannot fail.
+func Decode(mem memory.Bus, addr uint16) Instruction {
+	opcode := mem.Read(addr)
+	template := templates[opcode]
+
+	inst := Instruction{Address: addr, OpCode: cpu.OpCode(opcode), Length: 1}
+	if strings.HasPrefix(template, "*") {
+		inst.Undocumented = true
+		template = template[1:]
+	}
+
+	fields := strings.SplitN(template, " ", 2)
+	inst.Mnemonic = fields[0]
+	if len(fields) > 1 {
+		for _, operand := range strings.Split(fields[1], ",") {
+			inst.Operands = append(inst.Operands, inst.decodeOperand(mem, operand))
+		}
+	}
+
+	inst.Bytes = make([]uint8, inst.Length)
+	for i := range inst.Bytes {
+		inst.Bytes[i] = mem.Read(addr + uint16(i))
+	}
+
+	inst.Flow, inst.Target = flow(&inst)
+	return inst
+}
+
+// decodeOperand decodes an operand placeholder from templates, reading any operand bytes from mem and extending
+// the instruction's Length to cover them.
+func (inst *Instruction) decodeOperand(mem memory.Bus, operand string) Operand {
+	next := inst.Address + uint16(inst.Length)
+
+	switch operand {
+	case "d8", "p8":
+		inst.Length++
+		kind := Immediate8
+		if operand == "p8" {
+			kind = Port
+		}
+		return Operand{Kind: kind, Value: uint16(mem.Read(next))}
+	case "d16", "a16":
+		inst.Length += 2
+		kind := Immediate16
+		if operand == "a16" {
+			kind = Address
+		}
+		return Operand{Kind: kind, Value: uint16(mem.Read(next+1))<<8 | uint16(mem.Read(next))}
+	case "SP", "PSW":
+		return Operand{Kind: RegisterPair, Name: operand}
+	}
+
+	if vector, err := strconv.Atoi(operand); err == nil {
+		return Operand{Kind: Vector, Value: uint16(vector)}
+	}
+
+	// B, D and H name register pairs in the instructions that take a pair
+	switch inst.Mnemonic {
+	case "LXI", "STAX", "LDAX", "INX", "DCX", "DAD", "PUSH", "POP":
+		return Operand{Kind: RegisterPair, Name: operand}
+	}
+	return Operand{Kind: Register, Name: operand}
+}
+
+// flow classifies the instruction's effect on the flow of control and returns its branch target, if any.
+func flow(inst *Instruction) (Flow, uint16) {
+	switch inst.Mnemonic {
+	case "JMP":
+		return Jump, inst.Operands[0].Value
+	case "JNZ", "JZ", "JNC", "JC", "JPO", "JPE", "JP", "JM":
+		return ConditionalJump, inst.Operands[0].Value
+	case "CALL":
+		return Call, inst.Operands[0].Value
+	case "CNZ", "CZ", "CNC", "CC", "CPO", "CPE", "CP", "CM":
+		return ConditionalCall, inst.Operands[0].Value
+	case "RST":
+		return Call, inst.Operands[0].Value << 3
+	case "RET":
+		return Return, 0
+	case "RNZ", "RZ", "RNC", "RC", "RPO", "RPE", "RP", "RM":
+		return ConditionalReturn, 0
+	case "PCHL":
+		return IndirectJump, 0
+	case "HLT":
+		return Halt, 0
+	}
+	return Sequential, 0
+}
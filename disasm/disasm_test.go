@@ -0,0 +1,207 @@
+package disasm
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+func decodeBytes(code ...uint8) Instruction {
+	ram := memory.NewRAM()
+	copy(ram[0x100:], code)
+	return Decode(ram, 0x100)
+}
+
+func TestDecode_Lengths(t *testing.T) {
+	// Opcodes followed by a data byte or port, and by an address or 16-bit data word
+	twoByte := map[cpu.OpCode]bool{
+		cpu.MVIB: true, cpu.MVIC: true, cpu.MVID: true, cpu.MVIE: true, cpu.MVIH: true, cpu.MVIL: true, cpu.MVIM: true,
+		cpu.MVIA: true, cpu.ADI: true, cpu.ACI: true, cpu.SUI: true, cpu.SBI: true, cpu.ANI: true, cpu.XRI: true,
+		cpu.ORI: true, cpu.CPI: true, cpu.IN: true, cpu.OUT: true,
+	}
+	threeByte := map[cpu.OpCode]bool{
+		cpu.LXIB: true, cpu.LXID: true, cpu.LXIH: true, cpu.LXISP: true, cpu.SHLD: true, cpu.LHLD: true, cpu.STA: true,
+		cpu.LDA: true, cpu.JMP: true, cpu.JMPCB: true, cpu.CALL: true, cpu.CALLDD: true, cpu.CALLED: true,
+		cpu.CALLFD: true, cpu.JNZ: true, cpu.JZ: true, cpu.JNC: true, cpu.JC: true, cpu.JPO: true, cpu.JPE: true,
+		cpu.JP: true, cpu.JM: true, cpu.CNZ: true, cpu.CZ: true, cpu.CNC: true, cpu.CC: true, cpu.CPO: true,
+		cpu.CPE: true, cpu.CP: true, cpu.CM: true,
+	}
+
+	for opcode := 0; opcode < 256; opcode++ {
+		expected := 1
+		if twoByte[cpu.OpCode(opcode)] {
+			expected = 2
+		} else if threeByte[cpu.OpCode(opcode)] {
+			expected = 3
+		}
+
+		inst := decodeBytes(uint8(opcode), 0x34, 0x12)
+		if inst.Length != expected || len(inst.Bytes) != expected {
+			t.Errorf("Expected opcode 0x%02X (%s) to be %d bytes but decoded %d", opcode, inst, expected, inst.Length)
+		}
+		if inst.OpCode != cpu.OpCode(opcode) {
+			t.Errorf("Expected opcode 0x%02X but decoded 0x%02X", opcode, uint8(inst.OpCode))
+		}
+	}
+}
+
+// TestDecode_MatchesCPU checks templates against the cpu package, so that the two tables cannot drift apart. The
+// mnemonic and register operands of each template must spell the name of its cpu.OpCode constant, and the CPU must
+// advance past exactly the bytes the instruction decodes to.
+func TestDecode_MatchesCPU(t *testing.T) {
+	names := opCodeNames(t)
+	if len(names) != 256 {
+		t.Fatalf("Expected 256 cpu.OpCode constants but found %d", len(names))
+	}
+
+	for opcode := 0; opcode < 256; opcode++ {
+		template := strings.TrimPrefix(templates[opcode], "*")
+		name := strings.NewReplacer(" ", "", ",", "", "d16", "", "d8", "", "a16", "", "p8", "").Replace(template)
+		if strings.HasPrefix(templates[opcode], "*") {
+			name += fmt.Sprintf("%02X", opcode)
+		}
+		if name != names[opcode] {
+			t.Errorf("Expected opcode 0x%02X (%s) to be cpu.%s but it is cpu.%s", opcode, templates[opcode], name,
+				names[opcode])
+		}
+
+		// Every jump, call and return is aimed at the instruction that follows, so that PC ends up past the bytes
+		// the CPU consumed whether or not a condition holds. A restart goes to its vector.
+		c := new(cpu.CPU)
+		c.Init()
+		c.ProgramCounter = 0x0100
+		c.HL.Write16(0x0101)
+		c.SP.Write16(0x2000)
+		c.Memory.Write(0x2000, 0x01)
+		c.Memory.Write(0x2001, 0x01)
+		for i, b := range []uint8{uint8(opcode), 0x03, 0x01} {
+			c.Memory.Write(0x0100+uint16(i), b)
+		}
+
+		inst := Decode(c.Memory, 0x0100)
+		expected := 0x0100 + uint16(inst.Length)
+		if inst.Mnemonic == "RST" {
+			expected = inst.Target
+		}
+
+		c.StandardInstructionCycle()
+		if c.Fault != nil {
+			t.Errorf("Expected opcode 0x%02X (%s) to execute but the CPU faulted: %v", opcode, inst, c.Fault)
+		} else if c.ProgramCounter != expected {
+			t.Errorf("Expected opcode 0x%02X (%s) to leave PC at 0x%04X but it was 0x%04X", opcode, inst, expected,
+				c.ProgramCounter)
+		}
+	}
+}
+
+// opCodeNames returns the names of the cpu.OpCode constants, indexed by opcode.
+func opCodeNames(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../cpu/opcodes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.CONST {
+			for _, spec := range gen.Specs {
+				for _, name := range spec.(*ast.ValueSpec).Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestDecode_Operands(t *testing.T) {
+	var tests = []struct {
+		code     []uint8
+		mnemonic string
+		operands []Operand
+	}{
+		{[]uint8{uint8(cpu.MOVBM)}, "MOV", []Operand{{Kind: Register, Name: "B"}, {Kind: Register, Name: "M"}}},
+		{[]uint8{uint8(cpu.MVIA), 0x2A}, "MVI", []Operand{{Kind: Register, Name: "A"}, {Kind: Immediate8, Value: 0x2A}}},
+		{[]uint8{uint8(cpu.LXISP), 0x00, 0xF0}, "LXI", []Operand{{Kind: RegisterPair, Name: "SP"}, {Kind: Immediate16, Value: 0xF000}}},
+		{[]uint8{uint8(cpu.PUSHPSW)}, "PUSH", []Operand{{Kind: RegisterPair, Name: "PSW"}}},
+		{[]uint8{uint8(cpu.INXD)}, "INX", []Operand{{Kind: RegisterPair, Name: "D"}}},
+		{[]uint8{uint8(cpu.STA), 0x34, 0x12}, "STA", []Operand{{Kind: Address, Value: 0x1234}}},
+		{[]uint8{uint8(cpu.OUT), 0x10}, "OUT", []Operand{{Kind: Port, Value: 0x10}}},
+		{[]uint8{uint8(cpu.RST5)}, "RST", []Operand{{Kind: Vector, Value: 5}}},
+		{[]uint8{uint8(cpu.XCHG)}, "XCHG", nil},
+	}
+
+	for _, test := range tests {
+		inst := decodeBytes(test.code...)
+		if inst.Mnemonic != test.mnemonic || len(inst.Operands) != len(test.operands) {
+			t.Errorf("Expected %s with %v but decoded %s with %v", test.mnemonic, test.operands, inst.Mnemonic, inst.Operands)
+			continue
+		}
+		for i, op := range inst.Operands {
+			if op != test.operands[i] {
+				t.Errorf("%s: expected operand %d to be %+v but was %+v", test.mnemonic, i, test.operands[i], op)
+			}
+		}
+	}
+}
+
+func TestDecode_Flow(t *testing.T) {
+	var tests = []struct {
+		code   []uint8
+		flow   Flow
+		target uint16
+	}{
+		{[]uint8{uint8(cpu.NOP)}, Sequential, 0},
+		{[]uint8{uint8(cpu.JMP), 0x00, 0x02}, Jump, 0x0200},
+		{[]uint8{uint8(cpu.JNZ), 0x34, 0x12}, ConditionalJump, 0x1234},
+		{[]uint8{uint8(cpu.CALL), 0x05, 0x00}, Call, 0x0005},
+		{[]uint8{uint8(cpu.CM), 0x00, 0x80}, ConditionalCall, 0x8000},
+		{[]uint8{uint8(cpu.RST7)}, Call, 0x0038},
+		{[]uint8{uint8(cpu.RET)}, Return, 0},
+		{[]uint8{uint8(cpu.RPE)}, ConditionalReturn, 0},
+		{[]uint8{uint8(cpu.PCHL)}, IndirectJump, 0},
+		{[]uint8{uint8(cpu.HLT)}, Halt, 0},
+		{[]uint8{uint8(cpu.CALLFD), 0x00, 0x03}, Call, 0x0300},
+	}
+
+	for _, test := range tests {
+		inst := decodeBytes(test.code...)
+		if inst.Flow != test.flow || inst.Target != test.target {
+			t.Errorf("%s: expected flow %d to 0x%04X but got %d to 0x%04X", inst, test.flow, test.target, inst.Flow, inst.Target)
+		}
+		if inst.HasTarget() != (test.target != 0 || test.flow == Call) {
+			t.Errorf("%s: HasTarget returned %v", inst, inst.HasTarget())
+		}
+	}
+}
+
+func TestDecode_Undocumented(t *testing.T) {
+	inst := decodeBytes(uint8(cpu.JMPCB), 0x00, 0x02)
+	if !inst.Undocumented || inst.Mnemonic != "JMP" || inst.Target != 0x0200 {
+		t.Errorf("Expected undocumented JMP to 0x0200 but got %+v", inst)
+	}
+
+	if inst := decodeBytes(uint8(cpu.JMP), 0x00, 0x02); inst.Undocumented {
+		t.Error("Expected JMP 0xC3 to be documented")
+	}
+}
+
+func TestDecode_Wraparound(t *testing.T) {
+	ram := memory.NewRAM()
+	ram[0xFFFE] = uint8(cpu.LXIH)
+	ram[0xFFFF] = 0xCD
+	ram[0x0000] = 0xAB
+
+	inst := Decode(ram, 0xFFFE)
+	if inst.Operands[1].Value != 0xABCD || inst.Bytes[2] != 0xAB {
+		t.Errorf("Expected operand 0xABCD read across 0xFFFF but got %+v", inst)
+	}
+}
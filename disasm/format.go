@@ -0,0 +1,58 @@
+package disasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hex formats v in Intel notation: upper-case hexadecimal digits with an H suffix, and a leading 0 if the first
+// digit is a letter so that the result cannot be mistaken for a name. digits is the minimum number of digits.
+func Hex(v uint16, digits int) string {
+	s := fmt.Sprintf("%0*XH", digits, v)
+	if s[0] >= 'A' && s[0] <= 'F' {
+		s = "0" + s
+	}
+	return s
+}
+
+// String formats the operand in Intel syntax.
+func (op Operand) String() string {
+	switch op.Kind {
+	case Register, RegisterPair:
+		return op.Name
+	case Immediate8, Port:
+		return Hex(op.Value, 2)
+	case Immediate16, Address:
+		return Hex(op.Value, 4)
+	default:
+		return fmt.Sprint(op.Value)
+	}
+}
+
+// String formats the instruction in Intel syntax, such as "MVI A,2AH" or "JMP 0100H". An undocumented alias is
+// written with a leading * ("*CALL 1234H"), which assemblers do not accept.
+func (inst Instruction) String() string {
+	operands := make([]string, len(inst.Operands))
+	for i, op := range inst.Operands {
+		operands[i] = op.String()
+	}
+
+	mnemonic := inst.Mnemonic
+	if inst.Undocumented {
+		mnemonic = "*" + mnemonic
+	}
+	if len(operands) == 0 {
+		return mnemonic
+	}
+	return mnemonic + " " + strings.Join(operands, ",")
+}
+
+// Listing formats the instruction as a line of a disassembly listing: its address, its bytes in hexadecimal and its
+// Intel syntax, such as "0100  3E 2A     MVI A,2AH".
+func (inst Instruction) Listing() string {
+	bytes := make([]string, len(inst.Bytes))
+	for i, b := range inst.Bytes {
+		bytes[i] = fmt.Sprintf("%02X", b)
+	}
+	return fmt.Sprintf("%04X  %-8s  %s", inst.Address, strings.Join(bytes, " "), inst)
+}
@@ -0,0 +1,58 @@
+package disasm
+
+import (
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+func TestHex(t *testing.T) {
+	var tests = []struct {
+		v        uint16
+		digits   int
+		expected string
+	}{
+		{0x2A, 2, "2AH"},
+		{0xFF, 2, "0FFH"},
+		{0x0100, 4, "0100H"},
+		{0xC000, 4, "0C000H"},
+		{0x5, 1, "5H"},
+	}
+
+	for _, test := range tests {
+		if s := Hex(test.v, test.digits); s != test.expected {
+			t.Errorf("Expected %q but got %q", test.expected, s)
+		}
+	}
+}
+
+func TestInstruction_String(t *testing.T) {
+	var tests = []struct {
+		code     []uint8
+		expected string
+	}{
+		{[]uint8{uint8(cpu.NOP)}, "NOP"},
+		{[]uint8{uint8(cpu.MOVAM)}, "MOV A,M"},
+		{[]uint8{uint8(cpu.MVIA), 0xFF}, "MVI A,0FFH"},
+		{[]uint8{uint8(cpu.LXIB), 0x34, 0x12}, "LXI B,1234H"},
+		{[]uint8{uint8(cpu.JMP), 0x00, 0xC0}, "JMP 0C000H"},
+		{[]uint8{uint8(cpu.IN), 0x01}, "IN 01H"},
+		{[]uint8{uint8(cpu.RST3)}, "RST 3"},
+		{[]uint8{uint8(cpu.POPPSW)}, "POP PSW"},
+		{[]uint8{uint8(cpu.NOP38)}, "*NOP"},
+		{[]uint8{uint8(cpu.CALLDD), 0x00, 0x01}, "*CALL 0100H"},
+	}
+
+	for _, test := range tests {
+		if s := decodeBytes(test.code...).String(); s != test.expected {
+			t.Errorf("Expected %q but got %q", test.expected, s)
+		}
+	}
+}
+
+func TestInstruction_Listing(t *testing.T) {
+	expected := "0100  3E 2A     MVI A,2AH"
+	if s := decodeBytes(uint8(cpu.MVIA), 0x2A).Listing(); s != expected {
+		t.Errorf("Expected %q but got %q", expected, s)
+	}
+}
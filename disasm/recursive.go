@@ -0,0 +1,268 @@
+package disasm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// dataBytesPerLine is the most bytes listed in a single DB directive.
+const dataBytesPerLine = 8
+
+// minStringLength is the shortest run of printable characters listed as a string rather than as bytes.
+const minStringLength = 4
+
+// Disassembly is the result of following the flow of control through a program. Bytes reached from an entry point
+// are code and everything else is data. Jump and call targets and the addresses referenced by memory instructions
+// are given labels.
+type Disassembly struct {
+	Origin       uint16
+	Program      []byte
+	Instructions map[uint16]Instruction // Instructions by address
+	Labels       map[uint16]string      // Labels by address, inside and outside the program
+	mem          memory.RAM
+	code         []bool // Whether each byte of the program belongs to an instruction
+}
+
+// DefaultEntries returns the entry points of an 8080 executing from reset: 0x0000 and the eight restart vectors.
+func DefaultEntries() []uint16 {
+	return []uint16{0x0000, 0x0008, 0x0010, 0x0018, 0x0020, 0x0028, 0x0030, 0x0038}
+}
+
+// Disassemble follows the flow of control through program, loaded at origin, from each entry point inside it.
+// Conditional branches are followed both ways and calls are assumed to return. Decoding stops at an unconditional
+// jump, a return, PCHL or HLT, at the end of the program, or where an instruction would overlap one already
+// decoded. It returns an error if the program does not fit in the address space.
+func Disassemble(program []byte, origin uint16, entries ...uint16) (*Disassembly, error) {
+	if int(origin)+len(program) > memory.AddressSpaceSize {
+		return nil, fmt.Errorf("program of %d bytes loaded at 0x%04X does not fit in memory", len(program), origin)
+	}
+
+	d := &Disassembly{
+		Origin:       origin,
+		Program:      program,
+		Instructions: make(map[uint16]Instruction),
+		Labels:       make(map[uint16]string),
+		mem:          memory.NewRAM(),
+		code:         make([]bool, len(program)),
+	}
+	copy(d.mem[origin:], program)
+
+	work := append([]uint16(nil), entries...)
+	for len(work) > 0 {
+		addr := work[len(work)-1]
+		work = work[:len(work)-1]
+		work = append(work, d.trace(addr)...)
+	}
+
+	d.label()
+	return d, nil
+}
+
+// contains reports whether addr is inside the program.
+func (d *Disassembly) contains(addr uint16) bool {
+	return addr >= d.Origin && int(addr) < int(d.Origin)+len(d.Program)
+}
+
+// IsCode reports whether the byte at addr belongs to a decoded instruction.
+func (d *Disassembly) IsCode(addr uint16) bool {
+	return d.contains(addr) && d.code[addr-d.Origin]
+}
+
+// trace decodes instructions from addr until control leaves the straight-line path, and returns the branch targets
+// found along the way.
+func (d *Disassembly) trace(addr uint16) []uint16 {
+	var targets []uint16
+	for d.contains(addr) && !d.IsCode(addr) {
+		inst := Decode(d.mem, addr)
+		if int(addr)+inst.Length > int(d.Origin)+len(d.Program) {
+			break
+		}
+		for i := 1; i < inst.Length; i++ {
+			if d.IsCode(addr + uint16(i)) {
+				return targets
+			}
+		}
+
+		for i := 0; i < inst.Length; i++ {
+			d.code[addr+uint16(i)-d.Origin] = true
+		}
+		d.Instructions[addr] = inst
+
+		if inst.HasTarget() {
+			targets = append(targets, inst.Target)
+		}
+
+		switch inst.Flow {
+		case Jump, Return, IndirectJump, Halt:
+			return targets
+		}
+		addr += uint16(inst.Length)
+	}
+	return targets
+}
+
+// label names the targets of branches and the addresses referenced by memory instructions. Code labels start with
+// L and data labels with D. An address in the middle of an instruction cannot be labelled and is left as a number.
+func (d *Disassembly) label() {
+	addrs := make([]uint16, 0, len(d.Instructions))
+	for addr := range d.Instructions {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i] < addrs[j] })
+
+	for _, addr := range addrs {
+		inst := d.Instructions[addr]
+		if inst.HasTarget() {
+			// A restart vector is written as a number, so only label it where it can be defined
+			d.addLabel("L", inst.Target, inst.Mnemonic != "RST")
+			continue
+		}
+
+		switch inst.Mnemonic {
+		case "LDA", "STA", "LHLD", "SHLD":
+			d.addLabel("D", inst.Operands[0].Value, true)
+		case "LXI":
+			if inst.Operands[0].Name != "SP" {
+				d.addLabel("D", inst.Operands[1].Value, false)
+			}
+		}
+	}
+}
+
+// addLabel labels addr if it starts an instruction or data byte in the program, or, if external is set, if it is
+// outside the program.
+func (d *Disassembly) addLabel(prefix string, addr uint16, external bool) {
+	if _, ok := d.Labels[addr]; ok {
+		return
+	}
+
+	if d.contains(addr) {
+		if _, ok := d.Instructions[addr]; !ok && d.IsCode(addr) {
+			return
+		}
+	} else if !external {
+		return
+	}
+	d.Labels[addr] = fmt.Sprintf("%s%04X", prefix, addr)
+}
+
+// WriteSource writes the disassembly as assembly source that reassembles to the original program: labels outside
+// the program are defined with EQU, code is written as instructions and data with DB. Undocumented opcodes are
+// written with DB, since assemblers do not accept them, with the instruction in a comment.
+func (d *Disassembly) WriteSource(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	var external []uint16
+	for addr := range d.Labels {
+		if !d.contains(addr) {
+			external = append(external, addr)
+		}
+	}
+	sort.Slice(external, func(i, j int) bool { return external[i] < external[j] })
+	for _, addr := range external {
+		fmt.Fprintf(out, "%s\tEQU\t%s\n", d.Labels[addr], Hex(addr, 4))
+	}
+	if len(external) > 0 {
+		fmt.Fprintln(out)
+	}
+
+	fmt.Fprintf(out, "\tORG\t%s\n", Hex(d.Origin, 4))
+
+	end := int(d.Origin) + len(d.Program)
+	for addr := int(d.Origin); addr < end; {
+		if inst, ok := d.Instructions[uint16(addr)]; ok {
+			d.writeInstruction(out, inst)
+			addr += inst.Length
+			continue
+		}
+		addr = d.writeData(out, addr)
+	}
+
+	fmt.Fprintln(out, "\tEND")
+	return out.Flush()
+}
+
+// writeLine writes a line of source with an optional label, a mnemonic, operands and a comment.
+func (d *Disassembly) writeLine(w io.Writer, addr uint16, mnemonic string, operands string, comment string) {
+	line := d.Labels[addr]
+	if line != "" {
+		line += ":"
+	}
+	line += "\t" + mnemonic
+	if operands != "" {
+		line += "\t" + operands
+	}
+	if comment != "" {
+		line += "\t; " + comment
+	}
+	fmt.Fprintln(w, line)
+}
+
+func (d *Disassembly) writeInstruction(w io.Writer, inst Instruction) {
+	if inst.Undocumented {
+		d.writeLine(w, inst.Address, "DB", hexBytes(inst.Bytes), inst.String())
+		return
+	}
+
+	operands := make([]string, len(inst.Operands))
+	for i, op := range inst.Operands {
+		operands[i] = op.String()
+		if op.Kind == Address || op.Kind == Immediate16 {
+			if label, ok := d.Labels[op.Value]; ok {
+				operands[i] = label
+			}
+		}
+	}
+	d.writeLine(w, inst.Address, inst.Mnemonic, strings.Join(operands, ","), "")
+}
+
+// writeData writes a DB line for the data starting at addr and returns the address following it. A line ends
+// before the next instruction or label.
+func (d *Disassembly) writeData(w io.Writer, addr int) int {
+	end := addr + 1
+	for end < int(d.Origin)+len(d.Program) && end-addr < dataBytesPerLine*4 && !d.IsCode(uint16(end)) {
+		if _, ok := d.Labels[uint16(end)]; ok {
+			break
+		}
+		end++
+	}
+	data := d.Program[addr-int(d.Origin) : end-int(d.Origin)]
+
+	if n := printableRun(data); n >= minStringLength {
+		d.writeLine(w, uint16(addr), "DB", "'"+string(data[:n])+"'", "")
+		return addr + n
+	}
+
+	// List bytes up to the next string worth listing as such
+	n := 0
+	for n < len(data) && n < dataBytesPerLine && printableRun(data[n:]) < minStringLength {
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+	d.writeLine(w, uint16(addr), "DB", hexBytes(data[:n]), "")
+	return addr + n
+}
+
+// printableRun returns the number of leading bytes of data that can be written inside a quoted string.
+func printableRun(data []byte) int {
+	n := 0
+	for n < len(data) && data[n] >= ' ' && data[n] <= '~' && data[n] != '\'' {
+		n++
+	}
+	return n
+}
+
+func hexBytes(data []byte) string {
+	s := make([]string, len(data))
+	for i, b := range data {
+		s[i] = Hex(uint16(b), 2)
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,115 @@
+package disasm
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+// recursiveProgram is loaded at 0x100. It prints a message through the CP/M BDOS and returns.
+var recursiveProgram = []uint8{
+	uint8(cpu.JMP), 0x0C, 0x01, // 0x100
+	'H', 'E', 'L', 'L', 'O', '$', // 0x103: message
+	0x00, 0xFF, 0x01, // 0x109: data
+	uint8(cpu.LXID), 0x03, 0x01, // 0x10C
+	uint8(cpu.MVIC), 0x09, // 0x10F
+	uint8(cpu.CALL), 0x05, 0x00, // 0x111
+	uint8(cpu.LDA), 0x09, 0x01, // 0x114
+	uint8(cpu.ORAA),           // 0x117
+	uint8(cpu.JZ), 0x1E, 0x01, // 0x118
+	uint8(cpu.CALLDD), 0x1E, 0x01, // 0x11B: undocumented CALL
+	uint8(cpu.RET), // 0x11E
+	0x3E,           // 0x11F: never reached
+}
+
+func TestDisassemble(t *testing.T) {
+	d, err := Disassemble(recursiveProgram, 0x100, 0x100)
+	if err != nil {
+		t.Fatalf("Expected Disassemble to succeed but got %v", err)
+	}
+
+	for _, addr := range []uint16{0x100, 0x10C, 0x10F, 0x111, 0x114, 0x117, 0x118, 0x11B, 0x11E} {
+		if _, ok := d.Instructions[addr]; !ok {
+			t.Errorf("Expected an instruction at 0x%04X", addr)
+		}
+	}
+
+	for _, addr := range []uint16{0x103, 0x109, 0x10B, 0x11F} {
+		if d.IsCode(addr) {
+			t.Errorf("Expected 0x%04X to be data", addr)
+		}
+	}
+
+	var labels = map[uint16]string{
+		0x0005: "L0005",
+		0x0103: "D0103",
+		0x0109: "D0109",
+		0x010C: "L010C",
+		0x011E: "L011E",
+	}
+	for addr, expected := range labels {
+		if label := d.Labels[addr]; label != expected {
+			t.Errorf("Expected 0x%04X to be labelled %s but was %q", addr, expected, label)
+		}
+	}
+	if len(d.Labels) != len(labels) {
+		t.Errorf("Expected %d labels but got %v", len(labels), d.Labels)
+	}
+}
+
+func TestDisassembly_WriteSource(t *testing.T) {
+	d, err := Disassemble(recursiveProgram, 0x100, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var source bytes.Buffer
+	if err := d.WriteSource(&source); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `L0005	EQU	0005H
+
+	ORG	0100H
+	JMP	L010C
+D0103:	DB	'HELLO$'
+D0109:	DB	00H,0FFH,01H
+L010C:	LXI	D,D0103
+	MVI	C,09H
+	CALL	L0005
+	LDA	D0109
+	ORA	A
+	JZ	L011E
+	DB	0DDH,1EH,01H	; *CALL 011EH
+L011E:	RET
+	DB	3EH
+	END
+`
+	if source.String() != expected {
+		t.Errorf("Expected source\n%s\nbut got\n%s", expected, source.String())
+	}
+}
+
+func TestDisassemble_Overlap(t *testing.T) {
+	// The JMP lands in the middle of the LXI, so the bytes cannot be decoded both ways
+	program := []uint8{uint8(cpu.LXIH), uint8(cpu.NOP), uint8(cpu.RET), uint8(cpu.JMP), 0x01, 0x01}
+
+	d, err := Disassemble(program, 0x100, 0x100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := d.Instructions[0x101]; ok {
+		t.Error("Expected no instruction to be decoded inside the LXI")
+	}
+	if _, ok := d.Labels[0x101]; ok {
+		t.Error("Expected the middle of the LXI not to be labelled")
+	}
+}
+
+func TestDisassemble_TooLarge(t *testing.T) {
+	if _, err := Disassemble(make([]uint8, 0x200), 0xFF00); err == nil {
+		t.Error("Expected a program extending past 0xFFFF to be rejected")
+	}
+}
@@ -0,0 +1,110 @@
+package emulator
+
+import "io"
+
+const (
+	// consoleReady is read from the status port while input is waiting.
+	consoleReady uint8 = 0xFF
+
+	// consoleEOF is read from the data port once the input has been exhausted (CP/M's end-of-file, Ctrl-Z).
+	consoleEOF uint8 = 0x1A
+
+	// consoleBuffer is the number of input bytes buffered ahead of the guest.
+	consoleBuffer = 4096
+)
+
+// Console is an IODevice connecting the guest to a host terminal through two ports. Reading the status port
+// returns 0xFF while an input byte (or the end of the input) is waiting and 0x00 otherwise. Reading the data port
+// returns the next input byte, 0x00 if none is waiting, or 0x1A once the input is exhausted. Writing the data port
+// writes the byte to the output. Input is read on a separate goroutine so the CPU never blocks on the host.
+type Console struct {
+	StatusPort uint8
+	DataPort   uint8
+	out        io.Writer
+	input      chan uint8
+	pending    uint8
+	hasPending bool
+	eof        bool
+}
+
+// NewConsole creates a Console reading from in and writing to out on the given ports. in may be nil if the guest
+// takes no input.
+func NewConsole(in io.Reader, out io.Writer, statusPort uint8, dataPort uint8) *Console {
+	console := &Console{
+		StatusPort: statusPort,
+		DataPort:   dataPort,
+		out:        out,
+		input:      make(chan uint8, consoleBuffer),
+	}
+
+	if in == nil {
+		close(console.input)
+	} else {
+		go console.read(in)
+	}
+	return console
+}
+
+// read copies in to the input channel until it is exhausted.
+func (c *Console) read(in io.Reader) {
+	defer close(c.input)
+
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		for _, b := range buf[:n] {
+			c.input <- b
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// poll reports whether the data port has something to return: an input byte, or end-of-file.
+func (c *Console) poll() bool {
+	if c.hasPending || c.eof {
+		return true
+	}
+
+	select {
+	case b, ok := <-c.input:
+		if !ok {
+			c.eof = true
+		} else {
+			c.pending, c.hasPending = b, true
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// In implements cpu.IODevice.
+func (c *Console) In(port uint8) uint8 {
+	ready := c.poll()
+
+	if port == c.StatusPort {
+		if ready {
+			return consoleReady
+		}
+		return 0
+	}
+
+	switch {
+	case c.hasPending:
+		c.hasPending = false
+		return c.pending
+	case c.eof:
+		return consoleEOF
+	default:
+		return 0
+	}
+}
+
+// Out implements cpu.IODevice.
+func (c *Console) Out(port uint8, v uint8) {
+	if port == c.DataPort && c.out != nil {
+		c.out.Write([]byte{v})
+	}
+}
@@ -0,0 +1,56 @@
+package emulator
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitReady polls the console's status port until input (or end-of-file) is waiting.
+func waitReady(t *testing.T, console *Console) {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for console.In(console.StatusPort) != consoleReady {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected console input to become ready but it did not")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConsole_Input(t *testing.T) {
+	console := NewConsole(strings.NewReader("AB"), nil, 0, 1)
+
+	for _, expected := range []uint8{'A', 'B', consoleEOF, consoleEOF} {
+		waitReady(t, console)
+		if v := console.In(console.DataPort); v != expected {
+			t.Errorf("Expected 0x%X from the data port but read 0x%X", expected, v)
+		}
+	}
+}
+
+func TestConsole_NoInput(t *testing.T) {
+	console := NewConsole(nil, nil, 0, 1)
+
+	if v := console.In(console.StatusPort); v != consoleReady {
+		t.Errorf("Expected a console without input to report end-of-file as ready but read 0x%X", v)
+	}
+
+	if v := console.In(console.DataPort); v != consoleEOF {
+		t.Errorf("Expected 0x%X from the data port but read 0x%X", consoleEOF, v)
+	}
+}
+
+func TestConsole_Output(t *testing.T) {
+	var out bytes.Buffer
+	console := NewConsole(nil, &out, 0, 1)
+
+	console.Out(console.DataPort, 'X')
+	console.Out(console.StatusPort, 'Y')
+
+	if out.String() != "X" {
+		t.Errorf("Expected only the data port to write output but wrote %q", out.String())
+	}
+}
@@ -0,0 +1,190 @@
+package emulator
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// ErrPoweredOff is returned by TickCPU and Run once the CPU has been powered off.
+var ErrPoweredOff = errors.New("cpu powered off")
+
+// CPUInterface connects a CPU to the machine using it and to any peripherals. Peripherals running on
+// other goroutines post events (interrupt requests, reset, power off, data bus values) without blocking, and
+// the goroutine driving the CPU picks them up between instructions. CPU state and memory may be read
+// or written from any goroutine through the locked accessors.
+type CPUInterface struct {
+	cpu        *cpu.CPU
+	state      sync.Mutex // held while the CPU executes instructions
+	poweredOff bool
+
+	events     sync.Mutex // guards the pending host events below
+	interrupts [][]uint8
+	powerOff   bool
+	reset      bool
+	data       uint8
+	hasData    bool
+}
+
+// AttachDevice connects an I/O device to the given port so that IN and OUT instructions addressing it are
+// dispatched to the device.
+func (cpuInt *CPUInterface) AttachDevice(port uint8, device cpu.IODevice) {
+	cpuInt.state.Lock()
+	defer cpuInt.state.Unlock()
+	cpuInt.cpu.AttachDevice(port, device)
+}
+
+// RequestInterrupt raises the CPU's interrupt line on behalf of a device that will supply the given
+// instruction (normally an RST, or a 3-byte CALL). The CPU accepts the request once its interrupt system
+// is enabled. Requests are presented to the CPU in the order they are made.
+func (cpuInt *CPUInterface) RequestInterrupt(instruction ...uint8) {
+	cpuInt.events.Lock()
+	defer cpuInt.events.Unlock()
+	cpuInt.interrupts = append(cpuInt.interrupts, instruction)
+}
+
+// RequestPowerOff asks the CPU to stop before its next instruction. Its registers and memory remain
+// available for inspection afterwards.
+func (cpuInt *CPUInterface) RequestPowerOff() {
+	cpuInt.events.Lock()
+	defer cpuInt.events.Unlock()
+	cpuInt.powerOff = true
+}
+
+// RequestReset pulses the CPU's RESET line before its next instruction: execution restarts at 0x0000 with
+// interrupts disabled. Registers and memory are left as they are. See cpu.CPU.Reset.
+func (cpuInt *CPUInterface) RequestReset() {
+	cpuInt.events.Lock()
+	defer cpuInt.events.Unlock()
+	cpuInt.reset = true
+}
+
+// PutDataBus places v on the CPU's data bus before its next instruction.
+func (cpuInt *CPUInterface) PutDataBus(v uint8) {
+	cpuInt.events.Lock()
+	defer cpuInt.events.Unlock()
+	cpuInt.data = v
+	cpuInt.hasData = true
+}
+
+// DataBus returns the byte most recently placed on the CPU's data bus.
+func (cpuInt *CPUInterface) DataBus() uint8 {
+	var data uint8
+	cpuInt.Inspect(func(c *cpu.CPU) { c.DataBus.Read8(&data) })
+	return data
+}
+
+// Halted reports whether the CPU has executed a HLT instruction and is waiting for an interrupt.
+func (cpuInt *CPUInterface) Halted() bool {
+	var halted bool
+	cpuInt.Inspect(func(c *cpu.CPU) { halted = c.Halted })
+	return halted
+}
+
+// PoweredOff reports whether the CPU has been powered off.
+func (cpuInt *CPUInterface) PoweredOff() bool {
+	cpuInt.state.Lock()
+	defer cpuInt.state.Unlock()
+	return cpuInt.poweredOff
+}
+
+// Inspect calls f with the CPU while no instruction is executing, so that the host can safely read or modify
+// its registers, flags and memory. f must not retain the CPU after it returns.
+func (cpuInt *CPUInterface) Inspect(f func(c *cpu.CPU)) {
+	cpuInt.state.Lock()
+	defer cpuInt.state.Unlock()
+	f(cpuInt.cpu)
+}
+
+// ReadMemory returns the byte at addr. Memory-mapped devices are peeked rather than read, so that inspecting memory
+// has no side effects.
+func (cpuInt *CPUInterface) ReadMemory(addr uint16) uint8 {
+	var v uint8
+	cpuInt.Inspect(func(c *cpu.CPU) { v = memory.Peek(c.Memory, addr) })
+	return v
+}
+
+// WriteMemory stores v at addr. It returns the memory's fault, such as a *memory.WriteError, if the memory refused
+// the write; the fault is not left for the CPU to raise.
+func (cpuInt *CPUInterface) WriteMemory(addr uint16, v uint8) error {
+	var err error
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		c.Memory.Write(addr, v)
+		err = memory.TakeFault(c.Memory)
+	})
+	return err
+}
+
+// MemorySnapshot returns a copy of the entire 64 KiB address space taken between instructions. Like ReadMemory, it
+// peeks memory-mapped devices.
+func (cpuInt *CPUInterface) MemorySnapshot() []uint8 {
+	snapshot := make([]uint8, memory.AddressSpaceSize)
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		for addr := range snapshot {
+			snapshot[addr] = memory.Peek(c.Memory, uint16(addr))
+		}
+	})
+	return snapshot
+}
+
+// TickCPU services any pending host events and executes a single instruction cycle. It never blocks waiting
+// for peripherals. It returns ErrPoweredOff, without executing anything, once the CPU has been powered off, and
+// the CPU's Fault (such as an *cpu.UnimplementedOpcodeError) once the CPU has faulted.
+func (cpuInt *CPUInterface) TickCPU() error {
+	cpuInt.state.Lock()
+	defer cpuInt.state.Unlock()
+
+	_, err := cpuInt.step()
+	return err
+}
+
+// step services pending host events and executes one instruction, returning the number of T-states it
+// consumed and the CPU's Fault, if any. The caller must hold the state lock.
+func (cpuInt *CPUInterface) step() (int, error) {
+	if err := cpuInt.serviceEvents(); err != nil {
+		return 0, err
+	}
+	return cpuInt.cycle()
+}
+
+// serviceEvents applies pending host events to the CPU: power-off, reset, data bus writes and interrupt
+// requests. It returns ErrPoweredOff once the CPU has been powered off. The caller must hold the state lock.
+func (cpuInt *CPUInterface) serviceEvents() error {
+	if cpuInt.poweredOff {
+		return ErrPoweredOff
+	}
+
+	cpuInt.events.Lock()
+	defer cpuInt.events.Unlock()
+
+	if cpuInt.powerOff {
+		cpuInt.poweredOff = true
+		return ErrPoweredOff
+	}
+
+	if cpuInt.reset {
+		cpuInt.cpu.Reset()
+		cpuInt.reset = false
+	}
+
+	if cpuInt.hasData {
+		cpuInt.cpu.DataBus.Write8(cpuInt.data)
+		cpuInt.hasData = false
+	}
+
+	// Present the next interrupt request once the CPU has accepted the previous one
+	if len(cpuInt.interrupts) > 0 && !cpuInt.cpu.InterruptPending() {
+		cpuInt.cpu.RequestInterrupt(cpuInt.interrupts[0]...)
+		cpuInt.interrupts = cpuInt.interrupts[1:]
+	}
+	return nil
+}
+
+// cycle executes one instruction cycle, returning the number of T-states it consumed and the CPU's Fault, if
+// any. The caller must hold the state lock.
+func (cpuInt *CPUInterface) cycle() (int, error) {
+	cycles := cpuInt.cpu.StandardInstructionCycle()
+	return cycles, cpuInt.cpu.Fault
+}
@@ -0,0 +1,218 @@
+package emulator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+func TestCPUInterface_TickCPUPowerOff(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP)})
+	cpuInt.RequestPowerOff()
+
+	if err := cpuInt.TickCPU(); err != ErrPoweredOff {
+		t.Fatalf("Expected ErrPoweredOff but got %v", err)
+	}
+
+	if !cpuInt.PoweredOff() {
+		t.Error("Expected CPU to report powered off but it did not")
+	}
+
+	if err := cpuInt.TickCPU(); err != ErrPoweredOff {
+		t.Errorf("Expected ErrPoweredOff on subsequent tick but got %v", err)
+	}
+
+	// Final state must remain inspectable
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		if c.ProgramCounter != 0x100 {
+			t.Errorf("Expected PC to be preserved at 0x100 after power off but was 0x%X", c.ProgramCounter)
+		}
+	})
+
+	if cpuInt.ReadMemory(0x100) != uint8(cpu.NOP) {
+		t.Error("Expected memory to be preserved after power off but it was not")
+	}
+}
+
+func TestCPUInterface_TickCPUDoesNotBlock(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP), uint8(cpu.NOP)})
+
+	// With no peripherals posting events, ticks must execute without waiting on the host
+	for i := 0; i < 2; i++ {
+		if err := cpuInt.TickCPU(); err != nil {
+			t.Fatalf("Expected tick to succeed but got %v", err)
+		}
+	}
+
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		if c.ProgramCounter != 0x102 {
+			t.Errorf("Expected PC to be 0x102 but was 0x%X", c.ProgramCounter)
+		}
+	})
+}
+
+func TestCPUInterface_TickCPUUnimplementedOpcode(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP), uint8(cpu.NOP08)})
+	cpuInt.Inspect(func(c *cpu.CPU) { c.StrictOpcodes = true })
+
+	if err := cpuInt.TickCPU(); err != nil {
+		t.Fatalf("Expected first tick to succeed but got %v", err)
+	}
+
+	var unimplemented *cpu.UnimplementedOpcodeError
+	if err := cpuInt.TickCPU(); !errors.As(err, &unimplemented) {
+		t.Fatalf("Expected an UnimplementedOpcodeError but got %v", err)
+	}
+
+	if unimplemented.Address != 0x101 {
+		t.Errorf("Expected the error to report address 0x101 but reported 0x%X", unimplemented.Address)
+	}
+}
+
+func TestCPUInterface_RequestInterrupt(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP)})
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		c.SP.Write16(0x2000)
+		c.InterruptsEnabled = true
+	})
+
+	cpuInt.RequestInterrupt(uint8(cpu.RST2))
+	cpuInt.TickCPU()
+
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		if c.ProgramCounter != 0x10 {
+			t.Errorf("Expected RST 2 to transfer control to 0x10 but PC was 0x%X", c.ProgramCounter)
+		}
+	})
+}
+
+func TestCPUInterface_PutDataBus(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP)})
+	cpuInt.PutDataBus(0xAB)
+	cpuInt.TickCPU()
+
+	if data := cpuInt.DataBus(); data != 0xAB {
+		t.Errorf("Expected data bus to contain 0xAB but contained 0x%X", data)
+	}
+}
+
+func TestCPUInterface_MemorySnapshot(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP)})
+	cpuInt.WriteMemory(0xFFFF, 0xCD)
+
+	snapshot := cpuInt.MemorySnapshot()
+	if snapshot[0x100] != uint8(cpu.NOP) || snapshot[0xFFFF] != 0xCD {
+		t.Error("Expected snapshot to reflect memory contents but it did not")
+	}
+
+	// The snapshot must not alias the CPU's memory
+	snapshot[0xFFFF] = 0
+	if cpuInt.ReadMemory(0xFFFF) != 0xCD {
+		t.Error("Expected snapshot to be a copy but it modified memory")
+	}
+}
+
+// countingDevice is a memory-mapped device that counts its reads.
+type countingDevice struct {
+	reads int
+}
+
+func (d *countingDevice) Read(offset uint16) uint8 {
+	d.reads++
+	return 0
+}
+
+func (d *countingDevice) Write(offset uint16, v uint8) {}
+
+func TestCPUInterface_ReadMemoryPeeksDevices(t *testing.T) {
+	device := &countingDevice{}
+	bus, err := memory.NewMapBuilder().RAM("ram", 0x0000, 0x1000).Device("device", 0xF000, 0x10, device).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.NOP)}, WithMemory(bus))
+
+	cpuInt.ReadMemory(0xF000)
+	cpuInt.MemorySnapshot()
+
+	if device.reads != 0 {
+		t.Errorf("Expected host inspection not to read the device but it was read %d times", device.reads)
+	}
+}
+
+func TestCPUInterface_WriteMemoryReportsFault(t *testing.T) {
+	bus, err := memory.NewMapBuilder().
+		ROM("rom", 0x0000, []uint8{uint8(cpu.NOP), uint8(cpu.NOP)}, memory.WriteFault).
+		RAM("ram", 0x0100, 0x100).
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newTestMachine(t, []byte{uint8(cpu.NOP), uint8(cpu.HLT)}, WithMemory(bus))
+	m.ClockSpeed = Unthrottled
+
+	var writeErr *memory.WriteError
+	if err := m.WriteMemory(0x0001, uint8(cpu.INRA)); !errors.As(err, &writeErr) || writeErr.Address != 0x0001 {
+		t.Errorf("Expected WriteMemory to report the refused write to 0x0001 but got %v", err)
+	}
+
+	// The refused write is the host's, so the CPU must not raise it
+	if err := m.Run(context.Background()); err != nil {
+		t.Errorf("Expected Run to return nil after HLT but got %v", err)
+	}
+}
+
+// TestMachine_ConcurrentHostAccess exercises the host API from several goroutines while the CPU runs. It is
+// meaningful under go test -race.
+func TestMachine_ConcurrentHostAccess(t *testing.T) {
+	// LXI H,2000H; LOOP: INR M; JMP LOOP
+	m := newTestMachine(t, []byte{uint8(cpu.LXIH), 0x00, 0x20, uint8(cpu.INRM), uint8(cpu.JMP), 0x03, 0x01})
+	m.ClockSpeed = Unthrottled
+	m.Inspect(func(c *cpu.CPU) { c.SP.Write16(0xF000) })
+
+	result := make(chan error)
+	go func() { result <- m.Run(context.Background()) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				m.ReadMemory(0x2000)
+				m.Halted()
+				m.PutDataBus(uint8(j))
+				m.RequestInterrupt(uint8(cpu.NOP))
+			}
+			m.MemorySnapshot()
+		}()
+	}
+	wg.Wait()
+
+	m.RequestPowerOff()
+	if err := <-result; err != ErrPoweredOff {
+		t.Errorf("Expected Run to return ErrPoweredOff but got %v", err)
+	}
+}
+
+func TestCPUInterface_RequestReset(t *testing.T) {
+	cpuInt := newTestMachine(t, []byte{uint8(cpu.EI), uint8(cpu.NOP)})
+	cpuInt.WriteMemory(0x0000, uint8(cpu.NOP))
+
+	cpuInt.TickCPU()
+	cpuInt.RequestReset()
+	cpuInt.TickCPU()
+
+	cpuInt.Inspect(func(c *cpu.CPU) {
+		if c.ProgramCounter != 0x0001 {
+			t.Errorf("Expected execution to restart at 0x0000 but PC was 0x%X", c.ProgramCounter)
+		}
+		if c.InterruptsEnabled {
+			t.Error("Expected RESET to disable interrupts")
+		}
+	})
+}
@@ -0,0 +1,181 @@
+// Package emulator provides the host-facing API for running an Intel 8080 program: a Machine that owns a CPU and
+// its memory, accepts events from peripherals on other goroutines, and runs the CPU at a chosen clock speed.
+package emulator
+
+import (
+	"fmt"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// config holds the settings assembled from the Options passed to New.
+type config struct {
+	memorySize   int
+	memory       memory.Bus
+	loadAddress  uint16
+	entryPoint   uint16
+	hasEntry     bool
+	stack        uint16
+	hasStack     bool
+	powerOn      *cpu.PowerOnState
+	devices      map[uint8]cpu.IODevice
+	clockSpeed   int
+	stopOnHalt   bool
+	cycles       uint64
+	instructions uint64
+}
+
+// Option configures a Machine created by New.
+type Option func(*config)
+
+// WithMemorySize installs size bytes of RAM from address 0x0000. Addresses above the installed RAM read as
+// memory.FloatingBus and ignore writes. The default is the full 64 KiB address space.
+func WithMemorySize(size int) Option {
+	return func(c *config) {
+		c.memorySize = size
+	}
+}
+
+// WithMemory backs the address space with bus instead of RAM, for example a memory.Map laying out ROM, RAM and
+// mirrored regions. It takes precedence over WithMemorySize. The program is written through bus, so it must be
+// loaded into RAM; ROM contents belong in the bus itself.
+func WithMemory(bus memory.Bus) Option {
+	return func(c *config) {
+		c.memory = bus
+	}
+}
+
+// WithLoadAddress loads the program at addr instead of 0x0000.
+func WithLoadAddress(addr uint16) Option {
+	return func(c *config) {
+		c.loadAddress = addr
+	}
+}
+
+// WithEntryPoint starts execution at addr. The default is the load address.
+func WithEntryPoint(addr uint16) Option {
+	return func(c *config) {
+		c.entryPoint = addr
+		c.hasEntry = true
+	}
+}
+
+// WithStackPointer initializes SP to addr. The default is 0x0000, so that the first PUSH or CALL stores at the top
+// of the address space, or the value chosen by WithPowerOnState.
+func WithStackPointer(addr uint16) Option {
+	return func(c *config) {
+		c.stack = addr
+		c.hasStack = true
+	}
+}
+
+// WithPowerOnState fills the registers, flags and every byte of memory according to state before the program is
+// loaded. By default the registers are zeroed and the memory is left as it is: zeroed RAM, or the contents of the
+// bus passed to WithMemory.
+func WithPowerOnState(state cpu.PowerOnState) Option {
+	return func(c *config) {
+		c.powerOn = &state
+	}
+}
+
+// WithDevice attaches device to the given I/O port.
+func WithDevice(port uint8, device cpu.IODevice) Option {
+	return func(c *config) {
+		c.devices[port] = device
+	}
+}
+
+// WithClockSpeed sets the Machine's clock rate in Hz, or Unthrottled. The default is DefaultClockSpeed.
+func WithClockSpeed(hz int) Option {
+	return func(c *config) {
+		c.clockSpeed = hz
+	}
+}
+
+// WithStopOnHalt makes Run return at HLT even if interrupts are enabled. Use it for programs that have no
+// interrupt sources and signal completion by halting.
+func WithStopOnHalt() Option {
+	return func(c *config) {
+		c.stopOnHalt = true
+	}
+}
+
+// WithCycleLimit makes Run return ErrCycleLimit once the CPU has consumed limit T-states.
+func WithCycleLimit(limit uint64) Option {
+	return func(c *config) {
+		c.cycles = limit
+	}
+}
+
+// WithInstructionLimit makes Run return ErrInstructionLimit once the CPU has executed limit instructions.
+func WithInstructionLimit(limit uint64) Option {
+	return func(c *config) {
+		c.instructions = limit
+	}
+}
+
+// New creates a Machine, loads program into its memory and points the CPU at the entry point. The CPU starts with
+// interrupts disabled, and its registers and memory are filled first if WithPowerOnState is given. It returns an
+// error if the options are invalid, the program does not fit in memory or the memory refuses it.
+func New(program []byte, options ...Option) (*Machine, error) {
+	c := config{
+		memorySize: memory.AddressSpaceSize,
+		devices:    make(map[uint8]cpu.IODevice),
+		clockSpeed: DefaultClockSpeed,
+	}
+	for _, option := range options {
+		option(&c)
+	}
+
+	if c.memory == nil {
+		if c.memorySize <= 0 || c.memorySize > memory.AddressSpaceSize {
+			return nil, fmt.Errorf("memory size %d is outside the 8080 address space", c.memorySize)
+		}
+		c.memory = memory.NewRAMSize(c.memorySize)
+	} else {
+		c.memorySize = memory.AddressSpaceSize
+	}
+
+	if int(c.loadAddress)+len(program) > c.memorySize {
+		return nil, fmt.Errorf("program of %d bytes loaded at 0x%04X does not fit in %d bytes of memory",
+			len(program), c.loadAddress, c.memorySize)
+	}
+
+	if c.clockSpeed < 0 {
+		return nil, fmt.Errorf("invalid clock speed %d Hz", c.clockSpeed)
+	}
+
+	if !c.hasEntry {
+		c.entryPoint = c.loadAddress
+	}
+
+	mainCpu := &cpu.CPU{Memory: c.memory}
+	mainCpu.Init()
+	if c.powerOn != nil {
+		mainCpu.PowerOn(*c.powerOn)
+	}
+	mainCpu.ProgramCounter = c.entryPoint
+	if c.hasStack {
+		mainCpu.SP.Write16(c.stack)
+	}
+
+	for i, b := range program {
+		mainCpu.Memory.Write(c.loadAddress+uint16(i), b)
+	}
+	if err := memory.TakeFault(mainCpu.Memory); err != nil {
+		return nil, fmt.Errorf("loading program: %w", err)
+	}
+
+	for port, device := range c.devices {
+		mainCpu.AttachDevice(port, device)
+	}
+
+	return &Machine{
+		CPUInterface:     &CPUInterface{cpu: mainCpu},
+		ClockSpeed:       c.clockSpeed,
+		StopOnHalt:       c.stopOnHalt,
+		CycleLimit:       c.cycles,
+		InstructionLimit: c.instructions,
+	}, nil
+}
@@ -0,0 +1,155 @@
+package emulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+	"github.com/cbush06/intel8080emulator/memory"
+)
+
+// newTestMachine creates a Machine with program loaded at 0x100, where CP/M programs and most tests begin.
+func newTestMachine(t *testing.T, program []byte, options ...Option) *Machine {
+	t.Helper()
+
+	m, err := New(program, append([]Option{WithLoadAddress(0x100)}, options...)...)
+	if err != nil {
+		t.Fatalf("Expected New to succeed but got %v", err)
+	}
+	return m
+}
+
+type latchDevice struct {
+	value uint8
+}
+
+func (d *latchDevice) In(port uint8) uint8 {
+	return d.value
+}
+
+func (d *latchDevice) Out(port uint8, v uint8) {
+	d.value = v
+}
+
+func TestNew_Defaults(t *testing.T) {
+	m, err := New([]byte{uint8(cpu.MVIA), 0x2A})
+	if err != nil {
+		t.Fatalf("Expected New to succeed but got %v", err)
+	}
+
+	if m.ClockSpeed != DefaultClockSpeed {
+		t.Errorf("Expected clock speed %d but was %d", DefaultClockSpeed, m.ClockSpeed)
+	}
+
+	m.Inspect(func(c *cpu.CPU) {
+		if c.ProgramCounter != 0 {
+			t.Errorf("Expected PC to start at 0x0000 but was 0x%X", c.ProgramCounter)
+		}
+
+		if c.Memory.Read(0x0001) != 0x2A {
+			t.Error("Expected program to be loaded at 0x0000 but it was not")
+		}
+	})
+}
+
+func TestNew_Options(t *testing.T) {
+	device := &latchDevice{value: 0x5A}
+	program := []byte{uint8(cpu.NOP), uint8(cpu.IN), 0x10, uint8(cpu.HLT)}
+
+	m, err := New(program, WithMemorySize(0x4000), WithLoadAddress(0x200), WithEntryPoint(0x201),
+		WithStackPointer(0x3000), WithDevice(0x10, device), WithClockSpeed(Unthrottled))
+	if err != nil {
+		t.Fatalf("Expected New to succeed but got %v", err)
+	}
+
+	if err := m.TickCPU(); err != nil {
+		t.Fatalf("Expected tick to succeed but got %v", err)
+	}
+
+	m.Inspect(func(c *cpu.CPU) {
+		var a uint8
+		c.A.Read8(&a)
+		if a != 0x5A {
+			t.Errorf("Expected IN from the attached device to load 0x5A but loaded 0x%X", a)
+		}
+
+		if c.ProgramCounter != 0x203 {
+			t.Errorf("Expected execution to begin at the entry point and reach 0x203 but PC was 0x%X", c.ProgramCounter)
+		}
+
+		var sp uint16
+		c.SP.Read16(&sp)
+		if sp != 0x3000 {
+			t.Errorf("Expected SP to be 0x3000 but was 0x%X", sp)
+		}
+
+		if v := c.Memory.Read(0x4000); v != memory.FloatingBus {
+			t.Errorf("Expected address beyond the installed RAM to read 0x%X but read 0x%X", memory.FloatingBus, v)
+		}
+	})
+
+	if m.ClockSpeed != Unthrottled {
+		t.Errorf("Expected Machine to be unthrottled but clock speed was %d", m.ClockSpeed)
+	}
+}
+
+func TestNew_PowerOnState(t *testing.T) {
+	m, err := New([]byte{uint8(cpu.NOP)}, WithLoadAddress(0x100),
+		WithPowerOnState(cpu.PowerOnState{Policy: cpu.PowerOnPattern, Pattern: 0x76}))
+	if err != nil {
+		t.Fatalf("Expected New to succeed but got %v", err)
+	}
+
+	m.Inspect(func(c *cpu.CPU) {
+		if c.Memory.Read(0x0100) != uint8(cpu.NOP) {
+			t.Error("Expected the program to be loaded over the power-on pattern but it was not")
+		}
+
+		if c.Memory.Read(0x0101) != 0x76 {
+			t.Errorf("Expected memory to be filled with 0x76 but read 0x%X", c.Memory.Read(0x0101))
+		}
+
+		var sp uint16
+		c.SP.Read16(&sp)
+		if sp != 0x7676 {
+			t.Errorf("Expected SP to keep its power-on value 0x7676 but was 0x%X", sp)
+		}
+
+		if c.ProgramCounter != 0x0100 || c.InterruptsEnabled {
+			t.Errorf("Expected to start at 0x0100 with interrupts disabled but PC was 0x%X and INTE %v",
+				c.ProgramCounter, c.InterruptsEnabled)
+		}
+	})
+}
+
+func TestNew_Invalid(t *testing.T) {
+	var tests = []struct {
+		name    string
+		program []byte
+		options []Option
+	}{
+		{"memory too large", nil, []Option{WithMemorySize(memory.AddressSpaceSize + 1)}},
+		{"memory empty", nil, []Option{WithMemorySize(0)}},
+		{"program too large", make([]byte, 0x101), []Option{WithMemorySize(0x200), WithLoadAddress(0x100)}},
+		{"program past 64 KiB", make([]byte, 2), []Option{WithLoadAddress(0xFFFF)}},
+		{"negative clock speed", nil, []Option{WithClockSpeed(-1)}},
+	}
+
+	for _, test := range tests {
+		if _, err := New(test.program, test.options...); err == nil {
+			t.Errorf("%s: expected New to fail but it succeeded", test.name)
+		}
+	}
+}
+
+func TestNew_ProgramLoadedIntoROM(t *testing.T) {
+	bus, err := memory.NewMapBuilder().ROM("rom", 0x0000, make([]uint8, 0x100), memory.WriteFault).Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var writeErr *memory.WriteError
+	if _, err := New([]byte{uint8(cpu.HLT)}, WithMemory(bus)); !errors.As(err, &writeErr) {
+		t.Errorf("Expected New to report the refused write of the program but got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package emulator
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrCycleLimit is returned by Run when the CPU has consumed Machine.CycleLimit T-states.
+	ErrCycleLimit = errors.New("cycle limit reached")
+
+	// ErrInstructionLimit is returned by Run when the CPU has executed Machine.InstructionLimit instructions.
+	ErrInstructionLimit = errors.New("instruction limit reached")
+)
+
+const (
+	// DefaultClockSpeed is the Intel 8080's nominal clock rate of 2 MHz.
+	DefaultClockSpeed = 2000000
+
+	// Unthrottled may be assigned to Machine.ClockSpeed to execute instructions as fast as the host allows.
+	Unthrottled = 0
+)
+
+const (
+	// runSlice is the span of emulated time executed between checks of the host clock and the context.
+	runSlice = time.Millisecond
+
+	// unthrottledSliceCycles is the number of T-states executed between checks of the context when the
+	// Machine is unthrottled.
+	unthrottledSliceCycles = 100000
+
+	// maxClockLag is how far the emulated clock may fall behind the host clock before it stops trying to
+	// catch up. This keeps the Machine from running in a burst after the host stalls.
+	maxClockLag = 100 * time.Millisecond
+)
+
+// Machine drives a CPU in a continuous fetch-execute loop. Instructions are executed in batches, and between
+// batches the Machine sleeps as needed to hold the CPU to ClockSpeed.
+type Machine struct {
+	*CPUInterface
+	ClockSpeed       int    // Clock rate in Hz, or Unthrottled
+	StopOnHalt       bool   // Stop at HLT even if interrupts are enabled
+	CycleLimit       uint64 // Stop once the CPU has consumed this many T-states, or 0 for no limit
+	InstructionLimit uint64 // Stop once the CPU has executed this many instructions, or 0 for no limit
+}
+
+// Run executes instructions until ctx is cancelled, the CPU is powered off, a limit is reached or the CPU can make
+// no further progress. It returns ctx.Err() if the context was cancelled, ErrPoweredOff if the CPU was powered off,
+// ErrCycleLimit or ErrInstructionLimit if a limit was reached, the CPU's Fault if it faulted, or nil if the CPU
+// halted with interrupts disabled or StopOnHalt is set. While the CPU is halted with interrupts enabled and
+// StopOnHalt is not set, Run idles at ClockSpeed waiting for an interrupt. Host events are serviced between
+// instructions, and the CPU's state is unlocked for other goroutines between batches.
+func (m *Machine) Run(ctx context.Context) error {
+	start := time.Now()
+	var cycles uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		executed, stopped, err := m.execute(m.sliceCycles())
+		if stopped || err != nil {
+			return err
+		}
+		cycles += uint64(executed)
+
+		if m.ClockSpeed == Unthrottled {
+			continue
+		}
+
+		target := start.Add(time.Duration(cycles * uint64(time.Second) / uint64(m.ClockSpeed)))
+		if lag := time.Since(target); lag > maxClockLag {
+			start, cycles = time.Now(), 0
+		} else if lag < 0 {
+			time.Sleep(-lag)
+		}
+	}
+}
+
+// execute runs instructions until at least budget T-states have been consumed and returns the number consumed.
+// stopped is true if the CPU halted for good or err is set.
+func (m *Machine) execute(budget int) (executed int, stopped bool, err error) {
+	m.state.Lock()
+	defer m.state.Unlock()
+
+	for executed < budget {
+		// Events are serviced first, so that a reset requested while the CPU is stopped restarts it
+		if err := m.serviceEvents(); err != nil {
+			return executed, true, err
+		}
+
+		if m.cpu.Fault != nil {
+			return executed, true, m.cpu.Fault
+		}
+
+		if m.cpu.Halted && (m.StopOnHalt || !m.cpu.InterruptsEnabled) {
+			return executed, true, nil
+		}
+
+		if m.CycleLimit > 0 && m.cpu.Cycles >= m.CycleLimit {
+			return executed, true, ErrCycleLimit
+		}
+
+		if m.InstructionLimit > 0 && m.cpu.Instructions >= m.InstructionLimit {
+			return executed, true, ErrInstructionLimit
+		}
+
+		n, err := m.cycle()
+		if err != nil {
+			return executed, true, err
+		}
+
+		if n > 0 {
+			executed += n
+		} else {
+			// A halted CPU still consumes clock periods while it waits for an interrupt
+			executed = budget
+		}
+	}
+	return executed, false, nil
+}
+
+// sliceCycles returns the number of T-states to execute before the Machine next checks its context and clock.
+func (m *Machine) sliceCycles() int {
+	if m.ClockSpeed == Unthrottled {
+		return unthrottledSliceCycles
+	}
+
+	cycles := int(int64(m.ClockSpeed) * int64(runSlice) / int64(time.Second))
+	if cycles < 1 {
+		cycles = 1
+	}
+	return cycles
+}
@@ -0,0 +1,163 @@
+package emulator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cbush06/intel8080emulator/cpu"
+)
+
+func TestMachine_RunUntilHalt(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.DI), uint8(cpu.MVIA), 0x2A, uint8(cpu.HLT)})
+	m.ClockSpeed = Unthrottled
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Expected Run to return nil after HLT but got %v", err)
+	}
+
+	var a uint8
+	m.cpu.A.Read8(&a)
+	if a != 0x2A {
+		t.Errorf("Expected register A to contain 0x2A but contained 0x%X", a)
+	}
+}
+
+func TestMachine_RunAfterReset(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.INRA), uint8(cpu.HLT)}, WithLoadAddress(0x0000))
+	m.ClockSpeed = Unthrottled
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Expected Run to return nil after HLT but got %v", err)
+	}
+
+	// RESET releases the halted CPU, which restarts at 0x0000 and runs the program again
+	m.RequestReset()
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Expected Run to return nil after the second HLT but got %v", err)
+	}
+
+	var a uint8
+	m.cpu.A.Read8(&a)
+	if a != 2 {
+		t.Errorf("Expected register A to contain 2 after running twice but contained %d", a)
+	}
+	if m.cpu.ProgramCounter != 0x0002 || !m.cpu.Halted {
+		t.Errorf("Expected the CPU to halt again with PC 0x0002 but PC was 0x%04X (halted %v)",
+			m.cpu.ProgramCounter, m.cpu.Halted)
+	}
+	if m.cpu.Instructions != 4 {
+		t.Errorf("Expected 4 instructions to have executed but %d had", m.cpu.Instructions)
+	}
+}
+
+func TestMachine_RunCancelled(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.JMP), 0x00, 0x01})
+	m.ClockSpeed = Unthrottled
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := m.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected Run to stop with context.DeadlineExceeded but got %v", err)
+	}
+}
+
+func TestMachine_RunThrottled(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.JMP), 0x00, 0x01})
+	m.ClockSpeed = 100000 // 100 kHz
+
+	duration := 100 * time.Millisecond
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	started := time.Now()
+	m.Run(ctx)
+	elapsed := time.Since(started)
+
+	// The CPU may run at most one slice ahead of the host clock
+	limit := uint64(elapsed.Seconds()*float64(m.ClockSpeed)) + uint64(m.sliceCycles())
+	if m.cpu.Cycles > limit {
+		t.Errorf("Expected at most %d cycles in %v at %d Hz but executed %d", limit, elapsed, m.ClockSpeed, m.cpu.Cycles)
+	}
+
+	if m.cpu.Cycles == 0 {
+		t.Error("Expected the throttled Machine to execute instructions but it did not")
+	}
+}
+
+func TestMachine_RunPowerOff(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.MVIA), 0x2A, uint8(cpu.JMP), 0x02, 0x01})
+	m.ClockSpeed = Unthrottled
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.RequestPowerOff()
+	}()
+
+	if err := m.Run(context.Background()); err != ErrPoweredOff {
+		t.Fatalf("Expected Run to return ErrPoweredOff but got %v", err)
+	}
+
+	m.Inspect(func(c *cpu.CPU) {
+		var a uint8
+		c.A.Read8(&a)
+		if a != 0x2A {
+			t.Errorf("Expected register A to contain 0x2A after power off but contained 0x%X", a)
+		}
+	})
+
+	if err := m.Run(context.Background()); err != ErrPoweredOff {
+		t.Errorf("Expected Run to refuse to restart a powered off CPU but got %v", err)
+	}
+}
+
+func TestMachine_RunUnimplementedOpcode(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.NOP), uint8(cpu.JMPCB), 0x00, 0x01})
+	m.ClockSpeed = Unthrottled
+	m.Inspect(func(c *cpu.CPU) { c.StrictOpcodes = true })
+
+	var unimplemented *cpu.UnimplementedOpcodeError
+	if err := m.Run(context.Background()); !errors.As(err, &unimplemented) {
+		t.Fatalf("Expected Run to stop with an UnimplementedOpcodeError but got %v", err)
+	}
+
+	if unimplemented.OpCode != cpu.JMPCB || unimplemented.Address != 0x101 {
+		t.Errorf("Expected opcode 0xCB at 0x101 but got %v", unimplemented)
+	}
+}
+
+func TestMachine_RunStopOnHalt(t *testing.T) {
+	m := newTestMachine(t, []byte{uint8(cpu.EI), uint8(cpu.HLT)}, WithStopOnHalt(), WithClockSpeed(Unthrottled))
+
+	if err := m.Run(context.Background()); err != nil {
+		t.Fatalf("Expected Run to return nil at HLT but got %v", err)
+	}
+
+	if !m.Halted() {
+		t.Error("Expected the CPU to be halted but it was not")
+	}
+}
+
+func TestMachine_RunLimits(t *testing.T) {
+	loop := []byte{uint8(cpu.JMP), 0x00, 0x01}
+
+	m := newTestMachine(t, loop, WithCycleLimit(100), WithClockSpeed(Unthrottled))
+	if err := m.Run(context.Background()); err != ErrCycleLimit {
+		t.Errorf("Expected ErrCycleLimit but got %v", err)
+	}
+
+	if m.cpu.Cycles != 100 {
+		t.Errorf("Expected the CPU to stop after 100 cycles but executed %d", m.cpu.Cycles)
+	}
+
+	m = newTestMachine(t, loop, WithInstructionLimit(7), WithClockSpeed(Unthrottled))
+	if err := m.Run(context.Background()); err != ErrInstructionLimit {
+		t.Errorf("Expected ErrInstructionLimit but got %v", err)
+	}
+
+	if m.cpu.Instructions != 7 {
+		t.Errorf("Expected the CPU to stop after 7 instructions but executed %d", m.cpu.Instructions)
+	}
+}
@@ -0,0 +1,43 @@
+package memory
+
+// AddressSpaceSize is the number of bytes addressable by the Intel 8080's 16-bit address bus (64 KiB).
+const AddressSpaceSize = 0x10000
+
+// FloatingBus is the value read from an address that no memory drives.
+const FloatingBus uint8 = 0xFF
+
+// Bus is the interface through which the CPU fetches instructions and reads and writes memory. Implementations
+// may back the address space with RAM, ROM, mirrored regions or memory-mapped devices.
+type Bus interface {
+	Read(addr uint16) uint8
+	Write(addr uint16, v uint8)
+}
+
+// RAM is a flat block of read/write memory beginning at address 0x0000. Addresses beyond the end of a RAM smaller
+// than the address space read as FloatingBus and ignore writes.
+type RAM []uint8
+
+// NewRAM creates a RAM spanning the full 64 KiB address space.
+func NewRAM() RAM {
+	return make(RAM, AddressSpaceSize)
+}
+
+// NewRAMSize creates a RAM of size bytes installed from address 0x0000. size must not exceed AddressSpaceSize.
+func NewRAMSize(size int) RAM {
+	return make(RAM, size)
+}
+
+// Read returns the byte stored at addr.
+func (ram RAM) Read(addr uint16) uint8 {
+	if int(addr) >= len(ram) {
+		return FloatingBus
+	}
+	return ram[addr]
+}
+
+// Write stores v at addr.
+func (ram RAM) Write(addr uint16, v uint8) {
+	if int(addr) < len(ram) {
+		ram[addr] = v
+	}
+}
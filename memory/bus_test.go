@@ -0,0 +1,37 @@
+package memory
+
+import "testing"
+
+func TestNewRAM(t *testing.T) {
+	ram := NewRAM()
+	if len(ram) != AddressSpaceSize {
+		t.Errorf("Expected RAM to be %d bytes but was %d", AddressSpaceSize, len(ram))
+	}
+}
+
+func TestRAM_Read(t *testing.T) {
+	ram := NewRAM()
+	ram[0xFFFF] = 0xAB
+
+	if v := ram.Read(0xFFFF); v != 0xAB {
+		t.Errorf("Expected 0xAB but got 0x%X", v)
+	}
+}
+
+func TestRAM_Write(t *testing.T) {
+	ram := NewRAM()
+	ram.Write(0x4000, 0xCD)
+
+	if ram[0x4000] != 0xCD {
+		t.Errorf("Expected 0xCD but got 0x%X", ram[0x4000])
+	}
+}
+
+func TestRAM_BeyondSize(t *testing.T) {
+	ram := NewRAMSize(0x4000)
+	ram.Write(0x4000, 0xCD)
+
+	if v := ram.Read(0x4000); v != FloatingBus {
+		t.Errorf("Expected uninstalled address to read 0x%X but got 0x%X", FloatingBus, v)
+	}
+}
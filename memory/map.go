@@ -0,0 +1,320 @@
+package memory
+
+import (
+	"fmt"
+	"log"
+)
+
+// RegionKind identifies what a region of a Map decodes to.
+type RegionKind int
+
+const (
+	// RegionUnmapped is an address range that no memory drives. Reads return the region's ReadValue.
+	RegionUnmapped RegionKind = iota
+
+	// RegionRAM is read/write memory.
+	RegionRAM
+
+	// RegionROM is read-only memory. Writes are handled according to the region's write policy.
+	RegionROM
+
+	// RegionMirror is an address range that decodes to another range, as happens when a chip's upper address
+	// lines are not decoded.
+	RegionMirror
+
+	// RegionDevice is an address range claimed by a memory-mapped device. Every read and write in the range is
+	// passed to the region's Device.
+	RegionDevice
+)
+
+func (k RegionKind) String() string {
+	switch k {
+	case RegionRAM:
+		return "RAM"
+	case RegionROM:
+		return "ROM"
+	case RegionMirror:
+		return "mirror"
+	case RegionDevice:
+		return "device"
+	default:
+		return "unmapped"
+	}
+}
+
+// WritePolicy selects how a ROM or unmapped region handles a write.
+type WritePolicy int
+
+const (
+	// WriteIgnore discards the write.
+	WriteIgnore WritePolicy = iota
+
+	// WriteLog discards the write and logs it.
+	WriteLog
+
+	// WriteFault discards the write and reports a *WriteError through TakeFault, so that the CPU stops.
+	WriteFault
+)
+
+// Region is a range of addresses in a Map.
+type Region struct {
+	Name       string
+	Kind       RegionKind
+	Start      uint16
+	Size       int
+	Source     uint16      // First address of the range a mirror decodes to
+	SourceSize int         // Size of the range a mirror decodes to; the mirror repeats it to fill Size
+	ReadValue  uint8       // Value read from an unmapped region
+	Writes     WritePolicy // Handling of writes to a ROM or unmapped region
+	Contents   []byte      // Contents of a ROM, which may be shorter than Size
+	Device     Device      // Device claiming a device region
+}
+
+// contains reports whether addr falls in the region.
+func (r *Region) contains(addr int) bool {
+	return addr >= int(r.Start) && addr < int(r.Start)+r.Size
+}
+
+// WriteError is reported by a Map when a region with the WriteFault policy is written.
+type WriteError struct {
+	Region  string
+	Address uint16
+	Value   uint8
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("write of 0x%02X to read-only address 0x%04X in %s", e.Value, e.Address, e.Region)
+}
+
+// Device is a memory-mapped peripheral. Read and Write are called for every access to the device's region with the
+// offset of the accessed address from the start of the region, so a device can be placed at any address. Read may
+// have side effects, such as acknowledging a keyboard character.
+type Device interface {
+	Read(offset uint16) uint8
+	Write(offset uint16, v uint8)
+}
+
+// Peeker is implemented by a Bus or Device that can report the byte at an address without the side effects of Read.
+// A Device is passed the offset into its region, as for Read. The host uses it to inspect memory without disturbing
+// devices.
+type Peeker interface {
+	Peek(addr uint16) uint8
+}
+
+// Peek returns the byte at addr without side effects if bus implements Peeker, and reads it otherwise.
+func Peek(bus Bus, addr uint16) uint8 {
+	if peeker, ok := bus.(Peeker); ok {
+		return peeker.Peek(addr)
+	}
+	return bus.Read(addr)
+}
+
+// Faulter is implemented by a Bus that can refuse an access. TakeFault returns the first fault since the previous
+// call, or nil, and clears it. The CPU checks for a fault after every instruction.
+type Faulter interface {
+	TakeFault() error
+}
+
+// TakeFault returns and clears the pending fault of bus if it implements Faulter, and returns nil otherwise.
+func TakeFault(bus Bus) error {
+	if faulter, ok := bus.(Faulter); ok {
+		return faulter.TakeFault()
+	}
+	return nil
+}
+
+// Filler is implemented by a Bus whose RAM can be filled without the side effects of Write. Fill stores v at addr
+// if addr decodes to RAM and otherwise does nothing. The CPU fills memory through it at power-on.
+type Filler interface {
+	Fill(addr uint16, v uint8)
+}
+
+// maxRegions is the number of regions a Map can hold, including the default unmapped region.
+const maxRegions = 256
+
+// MapBuilder composes the regions of a Map. Addresses not covered by any region are unmapped: they read as
+// FloatingBus and ignore writes unless Default says otherwise. Errors are reported by Build.
+type MapBuilder struct {
+	background Region
+	regions    []Region
+}
+
+// NewMapBuilder creates a MapBuilder with no regions.
+func NewMapBuilder() *MapBuilder {
+	return &MapBuilder{
+		background: Region{Name: "unmapped", Kind: RegionUnmapped, Size: AddressSpaceSize, ReadValue: FloatingBus},
+	}
+}
+
+// Default sets the read value and write policy of addresses not covered by any region.
+func (b *MapBuilder) Default(readValue uint8, writes WritePolicy) *MapBuilder {
+	b.background.ReadValue = readValue
+	b.background.Writes = writes
+	return b
+}
+
+// RAM adds size bytes of RAM at start.
+func (b *MapBuilder) RAM(name string, start uint16, size int) *MapBuilder {
+	return b.Region(Region{Name: name, Kind: RegionRAM, Start: start, Size: size})
+}
+
+// ROM adds a ROM at start holding contents. Writes to it are handled according to writes.
+func (b *MapBuilder) ROM(name string, start uint16, contents []byte, writes WritePolicy) *MapBuilder {
+	return b.Region(Region{Name: name, Kind: RegionROM, Start: start, Size: len(contents), Writes: writes, Contents: contents})
+}
+
+// Mirror adds size bytes at start that decode to the sourceSize bytes at source, repeated as often as needed.
+func (b *MapBuilder) Mirror(name string, start uint16, size int, source uint16, sourceSize int) *MapBuilder {
+	return b.Region(Region{Name: name, Kind: RegionMirror, Start: start, Size: size, Source: source, SourceSize: sourceSize})
+}
+
+// Device adds size bytes at start claimed by device.
+func (b *MapBuilder) Device(name string, start uint16, size int, device Device) *MapBuilder {
+	return b.Region(Region{Name: name, Kind: RegionDevice, Start: start, Size: size, Device: device})
+}
+
+// Unmapped adds size bytes at start that no memory drives. Reads return readValue and writes are handled
+// according to writes.
+func (b *MapBuilder) Unmapped(name string, start uint16, size int, readValue uint8, writes WritePolicy) *MapBuilder {
+	return b.Region(Region{Name: name, Kind: RegionUnmapped, Start: start, Size: size, ReadValue: readValue, Writes: writes})
+}
+
+// Region adds r to the map. The helpers RAM, ROM, Mirror, Device and Unmapped cover the common cases.
+func (b *MapBuilder) Region(r Region) *MapBuilder {
+	b.regions = append(b.regions, r)
+	return b
+}
+
+// Build validates the regions and creates the Map. It returns an error if a region is empty, extends beyond the
+// address space or overlaps another region, or if a mirror decodes to another mirror. A mirror may decode to RAM,
+// ROM, unmapped addresses or a device, which then sees the accesses at the offsets they decode to.
+func (b *MapBuilder) Build() (*Map, error) {
+	if len(b.regions) >= maxRegions {
+		return nil, fmt.Errorf("memory map has %d regions; at most %d are supported", len(b.regions), maxRegions-1)
+	}
+
+	m := &Map{regions: append([]Region{b.background}, b.regions...)}
+	for i := 1; i < len(m.regions); i++ {
+		r := &m.regions[i]
+		if r.Size <= 0 || int(r.Start)+r.Size > AddressSpaceSize {
+			return nil, fmt.Errorf("region %s of %d bytes at 0x%04X is outside the address space", r.Name, r.Size, r.Start)
+		}
+		if r.Kind == RegionDevice && r.Device == nil {
+			return nil, fmt.Errorf("device region %s has no device", r.Name)
+		}
+
+		for j := 1; j < i; j++ {
+			if other := &m.regions[j]; other.contains(int(r.Start)) || r.contains(int(other.Start)) {
+				return nil, fmt.Errorf("region %s overlaps region %s", r.Name, other.Name)
+			}
+		}
+
+		for addr := int(r.Start); addr < int(r.Start)+r.Size; addr++ {
+			m.region[addr] = uint8(i)
+		}
+		if r.Kind == RegionROM {
+			copy(m.data[r.Start:int(r.Start)+r.Size], r.Contents)
+		}
+	}
+
+	for addr := range m.decode {
+		m.decode[addr] = uint16(addr)
+
+		r := &m.regions[m.region[addr]]
+		if r.Kind != RegionMirror {
+			continue
+		}
+
+		if r.SourceSize <= 0 || int(r.Source)+r.SourceSize > AddressSpaceSize {
+			return nil, fmt.Errorf("mirror %s of %d bytes at 0x%04X is outside the address space", r.Name, r.SourceSize, r.Source)
+		}
+
+		target := int(r.Source) + (addr-int(r.Start))%r.SourceSize
+		if m.regions[m.region[target]].Kind == RegionMirror {
+			return nil, fmt.Errorf("mirror %s decodes to another mirror at 0x%04X", r.Name, target)
+		}
+		m.decode[addr] = uint16(target)
+	}
+
+	return m, nil
+}
+
+// Map is a Bus composed of RAM, ROM, mirrored, device and unmapped regions. Every address is decoded when the Map is
+// built, so an access to RAM or ROM costs two table lookups whatever the layout.
+type Map struct {
+	data    [AddressSpaceSize]uint8
+	decode  [AddressSpaceSize]uint16 // The address each address decodes to; differs from it only in mirrors
+	region  [AddressSpaceSize]uint8  // Index into regions of the region containing each address
+	regions []Region
+	fault   error
+}
+
+// Region returns the region containing addr. Addresses not covered by any region belong to the default unmapped
+// region.
+func (m *Map) Region(addr uint16) Region {
+	return m.regions[m.region[addr]]
+}
+
+// Read returns the byte at addr. A read from a device region is passed to the device.
+func (m *Map) Read(addr uint16) uint8 {
+	decoded := m.decode[addr]
+	switch r := &m.regions[m.region[decoded]]; r.Kind {
+	case RegionRAM, RegionROM:
+		return m.data[decoded]
+	case RegionDevice:
+		return r.Device.Read(decoded - r.Start)
+	default:
+		return r.ReadValue
+	}
+}
+
+// Peek implements Peeker. A device region reads as FloatingBus unless its device implements Peeker.
+func (m *Map) Peek(addr uint16) uint8 {
+	decoded := m.decode[addr]
+	if r := &m.regions[m.region[decoded]]; r.Kind == RegionDevice {
+		if peeker, ok := r.Device.(Peeker); ok {
+			return peeker.Peek(decoded - r.Start)
+		}
+		return FloatingBus
+	}
+	return m.Read(addr)
+}
+
+// Write stores v at addr if addr decodes to RAM, passes it to the device if addr decodes to a device region, and
+// otherwise applies the region's write policy.
+func (m *Map) Write(addr uint16, v uint8) {
+	decoded := m.decode[addr]
+	r := &m.regions[m.region[decoded]]
+	switch r.Kind {
+	case RegionRAM:
+		m.data[decoded] = v
+		return
+	case RegionDevice:
+		r.Device.Write(decoded-r.Start, v)
+		return
+	}
+
+	switch r.Writes {
+	case WriteLog:
+		log.Printf("write of 0x%02X to read-only address 0x%04X in %s ignored", v, addr, r.Name)
+	case WriteFault:
+		if m.fault == nil {
+			m.fault = &WriteError{Region: r.Name, Address: addr, Value: v}
+		}
+	}
+}
+
+// Fill implements Filler.
+func (m *Map) Fill(addr uint16, v uint8) {
+	decoded := m.decode[addr]
+	if m.regions[m.region[decoded]].Kind == RegionRAM {
+		m.data[decoded] = v
+	}
+}
+
+// TakeFault implements Faulter.
+func (m *Map) TakeFault() error {
+	err := m.fault
+	m.fault = nil
+	return err
+}
@@ -0,0 +1,233 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+)
+
+func buildMap(t *testing.T, b *MapBuilder) *Map {
+	t.Helper()
+
+	m, err := b.Build()
+	if err != nil {
+		t.Fatalf("Expected Build to succeed but got %v", err)
+	}
+	return m
+}
+
+func TestMap_RAM(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().RAM("ram", 0x2000, 0x400))
+
+	m.Write(0x23FF, 0xAB)
+	if v := m.Read(0x23FF); v != 0xAB {
+		t.Errorf("Expected 0xAB but got 0x%X", v)
+	}
+
+	m.Write(0x2400, 0xCD)
+	if v := m.Read(0x2400); v != FloatingBus {
+		t.Errorf("Expected address beyond the RAM to read 0x%X but got 0x%X", FloatingBus, v)
+	}
+}
+
+func TestMap_ROM(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().ROM("rom", 0x0000, []byte{0x31, 0x00, 0x24}, WriteIgnore))
+
+	m.Write(0x0001, 0xFF)
+	if v := m.Read(0x0001); v != 0x00 {
+		t.Errorf("Expected ROM to keep 0x00 but got 0x%X", v)
+	}
+
+	if err := m.TakeFault(); err != nil {
+		t.Errorf("Expected ignored write not to fault but got %v", err)
+	}
+}
+
+func TestMap_WriteFault(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().ROM("rom", 0x0000, make([]byte, 0x2000), WriteFault))
+
+	m.Write(0x1000, 0x12)
+	m.Write(0x1001, 0x34)
+
+	var writeErr *WriteError
+	if err := m.TakeFault(); !errors.As(err, &writeErr) {
+		t.Fatalf("Expected a WriteError but got %v", err)
+	}
+	if writeErr.Region != "rom" || writeErr.Address != 0x1000 || writeErr.Value != 0x12 {
+		t.Errorf("Expected the first refused write to be reported but got %v", writeErr)
+	}
+
+	if err := m.TakeFault(); err != nil {
+		t.Errorf("Expected TakeFault to clear the fault but got %v", err)
+	}
+}
+
+func TestMap_Mirror(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().
+		RAM("ram", 0x2000, 0x2000).
+		Mirror("ram mirror", 0x4000, 0xC000, 0x2000, 0x2000))
+
+	m.Write(0x2010, 0x5A)
+	for _, addr := range []uint16{0x4010, 0x6010, 0xE010} {
+		if v := m.Read(addr); v != 0x5A {
+			t.Errorf("Expected mirror address 0x%04X to read 0x5A but got 0x%X", addr, v)
+		}
+	}
+
+	m.Write(0xFFFF, 0xA5)
+	if v := m.Read(0x3FFF); v != 0xA5 {
+		t.Errorf("Expected write through the mirror to reach 0x3FFF but read 0x%X", v)
+	}
+}
+
+func TestMap_Unmapped(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().
+		Default(0x00, WriteIgnore).
+		Unmapped("hole", 0x8000, 0x100, 0x76, WriteFault))
+
+	if v := m.Read(0x0000); v != 0x00 {
+		t.Errorf("Expected default read value 0x00 but got 0x%X", v)
+	}
+	if v := m.Read(0x8000); v != 0x76 {
+		t.Errorf("Expected unmapped region to read 0x76 but got 0x%X", v)
+	}
+
+	m.Write(0x8000, 0x01)
+	if m.TakeFault() == nil {
+		t.Error("Expected write to the unmapped region to fault")
+	}
+}
+
+func TestMap_Fill(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().
+		ROM("rom", 0x0000, []byte{0x01}, WriteFault).
+		RAM("ram", 0x0001, 0x10))
+
+	for addr := 0; addr < AddressSpaceSize; addr++ {
+		m.Fill(uint16(addr), 0xE5)
+	}
+
+	if m.Read(0x0000) != 0x01 || m.Read(0x0001) != 0xE5 || m.Read(0x0011) != FloatingBus {
+		t.Error("Expected Fill to change only RAM")
+	}
+	if err := m.TakeFault(); err != nil {
+		t.Errorf("Expected Fill not to fault but got %v", err)
+	}
+}
+
+func TestMap_Region(t *testing.T) {
+	m := buildMap(t, NewMapBuilder().RAM("ram", 0x2000, 0x400))
+
+	if r := m.Region(0x2100); r.Name != "ram" || r.Kind != RegionRAM {
+		t.Errorf("Expected 0x2100 to be in the RAM region but got %+v", r)
+	}
+	if r := m.Region(0x0000); r.Kind != RegionUnmapped {
+		t.Errorf("Expected 0x0000 to be unmapped but got %+v", r)
+	}
+}
+
+// keyboard is a Device that latches one character, which is cleared when it is read.
+type keyboard struct {
+	key    uint8
+	writes map[uint16]uint8
+}
+
+func (k *keyboard) Read(offset uint16) uint8 {
+	key := k.key
+	k.key = 0
+	return key + uint8(offset)
+}
+
+func (k *keyboard) Write(offset uint16, v uint8) {
+	k.writes[offset] = v
+}
+
+func TestMap_Device(t *testing.T) {
+	kbd := &keyboard{key: 'A', writes: make(map[uint16]uint8)}
+	m := buildMap(t, NewMapBuilder().
+		RAM("ram", 0x0000, 0x8000).
+		Device("keyboard", 0xF000, 0x10, kbd).
+		Mirror("keyboard mirror", 0xF800, 0x10, 0xF000, 0x10))
+
+	if v := m.Read(0xF000); v != 'A' {
+		t.Errorf("Expected the device to be read but got 0x%X", v)
+	}
+	if v := m.Read(0xF000); v != 0 {
+		t.Errorf("Expected the read to clear the latched key but got 0x%X", v)
+	}
+
+	m.Write(0xF003, 0x12)
+	m.Write(0xF805, 0x34)
+	if kbd.writes[3] != 0x12 || kbd.writes[5] != 0x34 {
+		t.Errorf("Expected writes at offsets 3 and 5 but got %v", kbd.writes)
+	}
+}
+
+func TestMap_MirrorOfDevice(t *testing.T) {
+	kbd := &keyboard{key: 'A', writes: make(map[uint16]uint8)}
+	m := buildMap(t, NewMapBuilder().
+		Device("keyboard", 0xF000, 0x10, kbd).
+		Mirror("keyboard mirror", 0xF800, 0x40, 0xF000, 0x10))
+
+	if v := m.Read(0xF822); v != 'A'+2 {
+		t.Errorf("Expected the mirror to read the device at offset 2 but got 0x%X", v)
+	}
+	if v := m.Read(0xF000); v != 0 {
+		t.Errorf("Expected the read through the mirror to clear the latched key but got 0x%X", v)
+	}
+}
+
+func TestMap_Peek(t *testing.T) {
+	kbd := &keyboard{key: 'A', writes: make(map[uint16]uint8)}
+	m := buildMap(t, NewMapBuilder().RAM("ram", 0x0000, 0x100).Device("keyboard", 0xF000, 0x10, kbd))
+	m.Write(0x0010, 0x5A)
+
+	if v := Peek(m, 0x0010); v != 0x5A {
+		t.Errorf("Expected to peek 0x5A from RAM but got 0x%X", v)
+	}
+	if v := Peek(m, 0xF000); v != FloatingBus {
+		t.Errorf("Expected a device without Peek to peek as 0x%X but got 0x%X", FloatingBus, v)
+	}
+	if kbd.key != 'A' {
+		t.Error("Expected Peek not to read the device")
+	}
+}
+
+func BenchmarkMap_ReadRAM(b *testing.B) {
+	m, _ := NewMapBuilder().
+		RAM("ram", 0x0000, 0x8000).
+		Device("keyboard", 0xF000, 0x10, &keyboard{writes: make(map[uint16]uint8)}).
+		Build()
+
+	for i := 0; i < b.N; i++ {
+		m.Read(uint16(i) & 0x7FFF)
+	}
+}
+
+func BenchmarkRAM_Read(b *testing.B) {
+	ram := NewRAM()
+
+	for i := 0; i < b.N; i++ {
+		ram.Read(uint16(i) & 0x7FFF)
+	}
+}
+
+func TestMapBuilder_Invalid(t *testing.T) {
+	var tests = []struct {
+		name    string
+		builder *MapBuilder
+	}{
+		{"empty", NewMapBuilder().RAM("ram", 0, 0)},
+		{"beyond the address space", NewMapBuilder().RAM("ram", 0xF000, 0x2000)},
+		{"overlap", NewMapBuilder().RAM("ram", 0x1000, 0x1000).ROM("rom", 0x1800, make([]byte, 0x100), WriteIgnore)},
+		{"enclosing overlap", NewMapBuilder().RAM("ram", 0x1800, 0x10).RAM("big", 0x1000, 0x1000)},
+		{"mirror source", NewMapBuilder().Mirror("mirror", 0x4000, 0x100, 0xFF00, 0x200)},
+		{"device", NewMapBuilder().Device("device", 0x4000, 0x100, nil)},
+		{"mirror of a mirror", NewMapBuilder().Mirror("a", 0x4000, 0x100, 0x5000, 0x100).Mirror("b", 0x5000, 0x100, 0x6000, 0x100)},
+	}
+
+	for _, test := range tests {
+		if _, err := test.builder.Build(); err == nil {
+			t.Errorf("%s: expected Build to fail but it succeeded", test.name)
+		}
+	}
+}